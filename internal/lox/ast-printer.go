@@ -3,6 +3,8 @@ package lox
 import (
 	"fmt"
 	"github.com/LucDeCaf/go-lox/internal/lox/ast"
+	"math/big"
+	"strings"
 )
 
 type AstPrinter struct{}
@@ -18,7 +20,7 @@ func (a *AstPrinter) Print(e ast.Expr) string {
 
 func (a *AstPrinter) VisitLiteralExpr(l *ast.LiteralExpr) any {
 	switch v := l.Value.(type) {
-	case float64:
+	case int64, *big.Int, float64:
 		return fmt.Sprintf("%v", v)
 	case string:
 		return "'" + v + "'"
@@ -50,3 +52,88 @@ func (a *AstPrinter) VisitUnaryExpr(u *ast.UnaryExpr) any {
 func (a *AstPrinter) VisitVariableExpr(u *ast.VariableExpr) any {
 	return u.Name.Lexeme
 }
+
+func (a *AstPrinter) VisitCallExpr(c *ast.CallExpr) any {
+	args := ""
+	for _, arg := range c.Arguments {
+		args += " " + arg.Accept(a).(string)
+	}
+	return fmt.Sprintf("(call %s%s)", c.Callee.Accept(a), args)
+}
+
+func (a *AstPrinter) VisitInterpolationExpr(e *ast.InterpolationExpr) any {
+	parts := ""
+	for _, part := range e.Parts {
+		parts += " " + part.Accept(a).(string)
+	}
+	return fmt.Sprintf("(interpolate%s)", parts)
+}
+
+func (a *AstPrinter) VisitAssignExpr(e *ast.AssignExpr) any {
+	return fmt.Sprintf("(= %s %s)", e.Name.Lexeme, e.Value.Accept(a))
+}
+
+func (a *AstPrinter) VisitListExpr(e *ast.ListExpr) any {
+	elements := ""
+	for _, element := range e.Elements {
+		elements += " " + element.Accept(a).(string)
+	}
+	return fmt.Sprintf("(list%s)", elements)
+}
+
+func (a *AstPrinter) VisitMapExpr(e *ast.MapExpr) any {
+	entries := ""
+	for idx, key := range e.Keys {
+		entries += fmt.Sprintf(" (%s %s)", key.Accept(a), e.Values[idx].Accept(a))
+	}
+	return fmt.Sprintf("(map%s)", entries)
+}
+
+func (a *AstPrinter) VisitIndexExpr(e *ast.IndexExpr) any {
+	return fmt.Sprintf("(index %s %s)", e.Object.Accept(a), e.Index.Accept(a))
+}
+
+func (a *AstPrinter) VisitCompoundAssignExpr(e *ast.CompoundAssignExpr) any {
+	return fmt.Sprintf("(%s %s %s)", e.Operator.Lexeme, e.Target.Accept(a), e.Value.Accept(a))
+}
+
+func (a *AstPrinter) VisitUpdateExpr(e *ast.UpdateExpr) any {
+	if e.Prefix {
+		return fmt.Sprintf("(%s %s)", e.Operator.Lexeme, e.Target.Accept(a))
+	}
+	return fmt.Sprintf("(%s %s)", e.Target.Accept(a), e.Operator.Lexeme)
+}
+
+func (a *AstPrinter) VisitIndexSetExpr(e *ast.IndexSetExpr) any {
+	return fmt.Sprintf("(index= %s %s %s)", e.Object.Accept(a), e.Index.Accept(a), e.Value.Accept(a))
+}
+
+func (a *AstPrinter) VisitGetExpr(e *ast.GetExpr) any {
+	if e.Optional {
+		return fmt.Sprintf("(?. %s %s)", e.Object.Accept(a), e.Name.Lexeme)
+	}
+	return fmt.Sprintf("(. %s %s)", e.Object.Accept(a), e.Name.Lexeme)
+}
+
+func (a *AstPrinter) VisitOptionalChainExpr(e *ast.OptionalChainExpr) any {
+	return fmt.Sprintf("(?chain %s)", e.Expression.Accept(a))
+}
+
+func (a *AstPrinter) VisitConditionalExpr(e *ast.ConditionalExpr) any {
+	return fmt.Sprintf("(? %s %s %s)", e.Condition.Accept(a), e.ThenBranch.Accept(a), e.ElseBranch.Accept(a))
+}
+
+func (a *AstPrinter) VisitFunctionExpr(e *ast.FunctionExpr) any {
+	params := make([]string, len(e.Params))
+	for idx, param := range e.Params {
+		params[idx] = param.Lexeme
+	}
+	if e.Name != nil {
+		return fmt.Sprintf("(fun %s (%s))", e.Name.Lexeme, strings.Join(params, " "))
+	}
+	return fmt.Sprintf("(fun (%s))", strings.Join(params, " "))
+}
+
+func (a *AstPrinter) VisitLogicalExpr(e *ast.LogicalExpr) any {
+	return fmt.Sprintf("(%s %s %s)", e.Left.Accept(a), e.Right.Accept(a), e.Operator.Lexeme)
+}
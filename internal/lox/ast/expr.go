@@ -10,6 +10,20 @@ type ExprVisitor interface {
 	VisitGroupingExpr(*GroupingExpr) any
 	VisitUnaryExpr(*UnaryExpr) any
 	VisitVariableExpr(*VariableExpr) any
+	VisitCallExpr(*CallExpr) any
+	VisitInterpolationExpr(*InterpolationExpr) any
+	VisitAssignExpr(*AssignExpr) any
+	VisitCompoundAssignExpr(*CompoundAssignExpr) any
+	VisitUpdateExpr(*UpdateExpr) any
+	VisitListExpr(*ListExpr) any
+	VisitMapExpr(*MapExpr) any
+	VisitIndexExpr(*IndexExpr) any
+	VisitIndexSetExpr(*IndexSetExpr) any
+	VisitGetExpr(*GetExpr) any
+	VisitOptionalChainExpr(*OptionalChainExpr) any
+	VisitConditionalExpr(*ConditionalExpr) any
+	VisitLogicalExpr(*LogicalExpr) any
+	VisitFunctionExpr(*FunctionExpr) any
 }
 
 type LiteralExpr struct {
@@ -34,6 +48,99 @@ type VariableExpr struct {
 	Name *Token
 }
 
+type AssignExpr struct {
+	Name  *Token
+	Value Expr
+}
+
+// CompoundAssignExpr is an assignment such as a += b. Target is a variable
+// or index expression, evaluated only once.
+type CompoundAssignExpr struct {
+	Target   Expr
+	Operator *Token
+	Value    Expr
+}
+
+// UpdateExpr is an increment or decrement: ++x, --x, x++ or x--.
+type UpdateExpr struct {
+	Target   Expr
+	Operator *Token
+	Prefix   bool
+}
+
+type ListExpr struct {
+	Elements []Expr
+}
+
+type MapExpr struct {
+	Brace  *Token
+	Keys   []Expr
+	Values []Expr
+}
+
+type IndexExpr struct {
+	Object  Expr
+	Bracket *Token
+	Index   Expr
+}
+
+type IndexSetExpr struct {
+	Object  Expr
+	Bracket *Token
+	Index   Expr
+	Value   Expr
+}
+
+// GetExpr is a property access. An optional access (a?.b) whose object is
+// nil skips the rest of its enclosing OptionalChainExpr.
+type GetExpr struct {
+	Object   Expr
+	Name     *Token
+	Optional bool
+}
+
+// OptionalChainExpr wraps a chain of calls, property accesses and indexing
+// that contains an optional access. The chain evaluates to nil as soon as
+// an optional access finds a nil object, so a?.b.c() is nil when a is.
+type OptionalChainExpr struct {
+	Expression Expr
+}
+
+type ConditionalExpr struct {
+	Condition  Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+// LogicalExpr is a binary operator that may not evaluate its right operand.
+type LogicalExpr struct {
+	Left, Right Expr
+	Operator    *Token
+}
+
+// InterpolationExpr is a string literal containing ${...} expressions. Parts
+// alternates between string literals and the embedded expressions.
+type InterpolationExpr struct {
+	Parts []Expr
+}
+
+// FunctionExpr is a function, written fun name(params) { body } or
+// (params) => expr. The body of the arrow form is a single return statement.
+type FunctionExpr struct {
+	Keyword *Token
+	// Name is nil for an anonymous function. It names the function in
+	// stack traces.
+	Name   *Token
+	Params []*Token
+	Body   []Stmt
+}
+
+type CallExpr struct {
+	Callee    Expr
+	Paren     *Token
+	Arguments []Expr
+}
+
 func (expr *LiteralExpr) Accept(v ExprVisitor) any {
 	return v.VisitLiteralExpr(expr)
 }
@@ -53,3 +160,59 @@ func (expr *UnaryExpr) Accept(v ExprVisitor) any {
 func (expr *VariableExpr) Accept(v ExprVisitor) any {
 	return v.VisitVariableExpr(expr)
 }
+
+func (expr *CallExpr) Accept(v ExprVisitor) any {
+	return v.VisitCallExpr(expr)
+}
+
+func (expr *InterpolationExpr) Accept(v ExprVisitor) any {
+	return v.VisitInterpolationExpr(expr)
+}
+
+func (expr *AssignExpr) Accept(v ExprVisitor) any {
+	return v.VisitAssignExpr(expr)
+}
+
+func (expr *CompoundAssignExpr) Accept(v ExprVisitor) any {
+	return v.VisitCompoundAssignExpr(expr)
+}
+
+func (expr *UpdateExpr) Accept(v ExprVisitor) any {
+	return v.VisitUpdateExpr(expr)
+}
+
+func (expr *ListExpr) Accept(v ExprVisitor) any {
+	return v.VisitListExpr(expr)
+}
+
+func (expr *MapExpr) Accept(v ExprVisitor) any {
+	return v.VisitMapExpr(expr)
+}
+
+func (expr *IndexExpr) Accept(v ExprVisitor) any {
+	return v.VisitIndexExpr(expr)
+}
+
+func (expr *IndexSetExpr) Accept(v ExprVisitor) any {
+	return v.VisitIndexSetExpr(expr)
+}
+
+func (expr *GetExpr) Accept(v ExprVisitor) any {
+	return v.VisitGetExpr(expr)
+}
+
+func (expr *OptionalChainExpr) Accept(v ExprVisitor) any {
+	return v.VisitOptionalChainExpr(expr)
+}
+
+func (expr *ConditionalExpr) Accept(v ExprVisitor) any {
+	return v.VisitConditionalExpr(expr)
+}
+
+func (expr *LogicalExpr) Accept(v ExprVisitor) any {
+	return v.VisitLogicalExpr(expr)
+}
+
+func (expr *FunctionExpr) Accept(v ExprVisitor) any {
+	return v.VisitFunctionExpr(expr)
+}
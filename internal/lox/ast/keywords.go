@@ -5,19 +5,27 @@ var Keywords map[string]TokenType
 func init() {
 	Keywords = make(map[string]TokenType)
 	Keywords["and"] = AND
+	Keywords["break"] = BREAK
+	Keywords["catch"] = CATCH
 	Keywords["class"] = CLASS
+	Keywords["continue"] = CONTINUE
 	Keywords["else"] = ELSE
 	Keywords["false"] = FALSE
+	Keywords["finally"] = FINALLY
 	Keywords["for"] = FOR
 	Keywords["fun"] = FUN
 	Keywords["if"] = IF
+	Keywords["import"] = IMPORT
+	Keywords["in"] = IN
 	Keywords["nil"] = NIL
 	Keywords["or"] = OR
 	Keywords["print"] = PRINT
 	Keywords["return"] = RETURN
 	Keywords["super"] = SUPER
 	Keywords["this"] = THIS
+	Keywords["throw"] = THROW
 	Keywords["true"] = TRUE
+	Keywords["try"] = TRY
 	Keywords["var"] = VAR
 	Keywords["while"] = WHILE
 }
@@ -8,6 +8,14 @@ type StmtVisitor interface {
 	VisitExpressionStmt(*ExpressionStmt) error
 	VisitPrintStmt(*PrintStmt) error
 	VisitVarStmt(*VarStmt) error
+	VisitBlockStmt(*BlockStmt) error
+	VisitForInStmt(*ForInStmt) error
+	VisitBreakStmt(*BreakStmt) error
+	VisitContinueStmt(*ContinueStmt) error
+	VisitThrowStmt(*ThrowStmt) error
+	VisitTryStmt(*TryStmt) error
+	VisitImportStmt(*ImportStmt) error
+	VisitReturnStmt(*ReturnStmt) error
 }
 
 type ExpressionStmt struct {
@@ -23,6 +31,58 @@ type VarStmt struct {
 	Value Expr
 }
 
+type BlockStmt struct {
+	Statements []Stmt
+}
+
+// ForInStmt is a for (name in iterable) loop.
+type ForInStmt struct {
+	Name     *Token
+	In       *Token
+	Iterable Expr
+	Body     Stmt
+}
+
+// BreakStmt exits the innermost enclosing loop, running any finally blocks
+// between it and the loop.
+type BreakStmt struct {
+	Keyword *Token
+}
+
+// ContinueStmt skips to the next iteration of the innermost enclosing loop.
+type ContinueStmt struct {
+	Keyword *Token
+}
+
+// ReturnStmt returns from the enclosing function. Value is nil for a bare
+// return.
+type ReturnStmt struct {
+	Keyword *Token
+	Value   Expr
+}
+
+type ThrowStmt struct {
+	Keyword *Token
+	Value   Expr
+}
+
+// TryStmt is a try block with an optional catch clause and an optional
+// finally clause. At least one of the two is present.
+type TryStmt struct {
+	Body        []Stmt
+	CatchName   *Token
+	CatchBody   []Stmt
+	FinallyBody []Stmt
+}
+
+// ImportStmt is import "path" as name, binding the module's top-level
+// declarations to name.
+type ImportStmt struct {
+	Keyword *Token
+	Path    *Token
+	Name    *Token
+}
+
 func (s *ExpressionStmt) Accept(v StmtVisitor) error {
 	return v.VisitExpressionStmt(s)
 }
@@ -34,3 +94,35 @@ func (s *PrintStmt) Accept(v StmtVisitor) error {
 func (s *VarStmt) Accept(v StmtVisitor) error {
 	return v.VisitVarStmt(s)
 }
+
+func (s *BlockStmt) Accept(v StmtVisitor) error {
+	return v.VisitBlockStmt(s)
+}
+
+func (s *ForInStmt) Accept(v StmtVisitor) error {
+	return v.VisitForInStmt(s)
+}
+
+func (s *BreakStmt) Accept(v StmtVisitor) error {
+	return v.VisitBreakStmt(s)
+}
+
+func (s *ContinueStmt) Accept(v StmtVisitor) error {
+	return v.VisitContinueStmt(s)
+}
+
+func (s *ThrowStmt) Accept(v StmtVisitor) error {
+	return v.VisitThrowStmt(s)
+}
+
+func (s *TryStmt) Accept(v StmtVisitor) error {
+	return v.VisitTryStmt(s)
+}
+
+func (s *ImportStmt) Accept(v StmtVisitor) error {
+	return v.VisitImportStmt(s)
+}
+
+func (s *ReturnStmt) Accept(v StmtVisitor) error {
+	return v.VisitReturnStmt(s)
+}
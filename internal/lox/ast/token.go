@@ -9,38 +9,72 @@ const (
 	RIGHT_PAREN
 	LEFT_BRACE
 	RIGHT_BRACE
+	LEFT_BRACKET
+	RIGHT_BRACKET
 	COMMA
+	COLON
 	DOT
 	MINUS
+	MINUS_MINUS
+	MINUS_EQUAL
 	PLUS
+	PLUS_PLUS
+	PLUS_EQUAL
 	SEMICOLON
 	SLASH
+	SLASH_EQUAL
 	STAR
+	STAR_EQUAL
+	STAR_STAR
+	PERCENT
+	PERCENT_EQUAL
+	TILDE
+	TILDE_SLASH
+	AMPERSAND
+	PIPE
+	CARET
+	LESS_LESS
+	GREATER_GREATER
 	BANG
 	BANG_EQUAL
 	EQUAL
 	EQUAL_EQUAL
+	ARROW
 	GREATER
 	GREATER_EQUAL
 	LESS
 	LESS_EQUAL
+	QUESTION
+	QUESTION_QUESTION
+	QUESTION_DOT
 	IDENTIFIER
 	STRING
+	INTERPOLATION
+	INTERPOLATION_MIDDLE
+	INTERPOLATION_END
 	NUMBER
 	AND
+	BREAK
+	CATCH
 	CLASS
+	CONTINUE
 	ELSE
 	FALSE
+	FINALLY
 	FUN
 	FOR
 	IF
+	IMPORT
+	IN
 	NIL
 	OR
 	PRINT
 	RETURN
 	SUPER
 	THIS
+	THROW
 	TRUE
+	TRY
 	VAR
 	WHILE
 	EOF
@@ -81,20 +115,58 @@ func (tt TokenType) String() string {
 		return "LEFT_BRACE"
 	case RIGHT_BRACE:
 		return "RIGHT_BRACE"
+	case LEFT_BRACKET:
+		return "LEFT_BRACKET"
+	case RIGHT_BRACKET:
+		return "RIGHT_BRACKET"
 	case COMMA:
 		return "COMMA"
+	case COLON:
+		return "COLON"
 	case DOT:
 		return "DOT"
 	case MINUS:
 		return "MINUS"
+	case MINUS_MINUS:
+		return "MINUS_MINUS"
+	case MINUS_EQUAL:
+		return "MINUS_EQUAL"
 	case PLUS:
 		return "PLUS"
+	case PLUS_PLUS:
+		return "PLUS_PLUS"
+	case PLUS_EQUAL:
+		return "PLUS_EQUAL"
 	case SEMICOLON:
 		return "SEMICOLON"
 	case SLASH:
 		return "SLASH"
+	case SLASH_EQUAL:
+		return "SLASH_EQUAL"
 	case STAR:
 		return "STAR"
+	case STAR_EQUAL:
+		return "STAR_EQUAL"
+	case STAR_STAR:
+		return "STAR_STAR"
+	case PERCENT:
+		return "PERCENT"
+	case PERCENT_EQUAL:
+		return "PERCENT_EQUAL"
+	case TILDE:
+		return "TILDE"
+	case TILDE_SLASH:
+		return "TILDE_SLASH"
+	case AMPERSAND:
+		return "AMPERSAND"
+	case PIPE:
+		return "PIPE"
+	case CARET:
+		return "CARET"
+	case LESS_LESS:
+		return "LESS_LESS"
+	case GREATER_GREATER:
+		return "GREATER_GREATER"
 	case BANG:
 		return "BANG"
 	case BANG_EQUAL:
@@ -103,6 +175,8 @@ func (tt TokenType) String() string {
 		return "EQUAL"
 	case EQUAL_EQUAL:
 		return "EQUAL_EQUAL"
+	case ARROW:
+		return "ARROW"
 	case GREATER:
 		return "GREATER"
 	case GREATER_EQUAL:
@@ -111,26 +185,50 @@ func (tt TokenType) String() string {
 		return "LESS"
 	case LESS_EQUAL:
 		return "LESS_EQUAL"
+	case QUESTION:
+		return "QUESTION"
+	case QUESTION_QUESTION:
+		return "QUESTION_QUESTION"
+	case QUESTION_DOT:
+		return "QUESTION_DOT"
 	case IDENTIFIER:
 		return "IDENTIFIER"
 	case STRING:
 		return "STRING"
+	case INTERPOLATION:
+		return "INTERPOLATION"
+	case INTERPOLATION_MIDDLE:
+		return "INTERPOLATION_MIDDLE"
+	case INTERPOLATION_END:
+		return "INTERPOLATION_END"
 	case NUMBER:
 		return "NUMBER"
 	case AND:
 		return "AND"
+	case BREAK:
+		return "BREAK"
+	case CATCH:
+		return "CATCH"
 	case CLASS:
 		return "CLASS"
+	case CONTINUE:
+		return "CONTINUE"
 	case ELSE:
 		return "ELSE"
 	case FALSE:
 		return "FALSE"
+	case FINALLY:
+		return "FINALLY"
 	case FUN:
 		return "FUN"
 	case FOR:
 		return "FOR"
 	case IF:
 		return "IF"
+	case IMPORT:
+		return "IMPORT"
+	case IN:
+		return "IN"
 	case NIL:
 		return "NIL"
 	case OR:
@@ -143,8 +241,12 @@ func (tt TokenType) String() string {
 		return "SUPER"
 	case THIS:
 		return "THIS"
+	case THROW:
+		return "THROW"
 	case TRUE:
 		return "TRUE"
+	case TRY:
+		return "TRY"
 	case VAR:
 		return "VAR"
 	case WHILE:
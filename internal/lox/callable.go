@@ -0,0 +1,72 @@
+package lox
+
+import "fmt"
+
+type LoxCallable interface {
+	// Name identifies the callable in stack traces.
+	Name() string
+	// Arity returns the number of arguments expected, or -1 if the callable
+	// accepts any number of arguments.
+	Arity() int
+	Call(i *Interpreter, args []any) (any, error)
+}
+
+// loxObject is implemented by built-in values that expose properties, such
+// as methods, through get expressions.
+type loxObject interface {
+	get(name string) (any, bool)
+}
+
+// indexable is implemented by values that support [] indexing.
+type indexable interface {
+	Get(index any) (any, error)
+	Set(index any, value any) error
+}
+
+type NativeFunction struct {
+	name  string
+	arity int
+	fn    func(i *Interpreter, args []any) (any, error)
+}
+
+// nativeError is returned by native functions, which have no token to attach
+// to. The interpreter converts it into a RuntimeError at the call site.
+type nativeError struct {
+	message string
+	stack   []StackFrame
+	// cause is the host error, such as a context cancellation, that
+	// aborted the native.
+	cause error
+}
+
+func (e *nativeError) Error() string {
+	return e.message
+}
+
+func (e *nativeError) Unwrap() error {
+	return e.cause
+}
+
+func NewNativeFunction(name string, arity int, fn func(*Interpreter, []any) (any, error)) *NativeFunction {
+	return &NativeFunction{
+		name:  name,
+		arity: arity,
+		fn:    fn,
+	}
+}
+
+func (n *NativeFunction) Name() string {
+	return n.name
+}
+
+func (n *NativeFunction) Arity() int {
+	return n.arity
+}
+
+func (n *NativeFunction) Call(i *Interpreter, args []any) (any, error) {
+	return n.fn(i, args)
+}
+
+func (n *NativeFunction) String() string {
+	return fmt.Sprintf("<native fn %s>", n.name)
+}
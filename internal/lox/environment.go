@@ -1,12 +1,21 @@
 package lox
 
 type Environment struct {
-	values map[string]any
+	values    map[string]any
+	enclosing *Environment
 }
 
 func NewEnvironment() *Environment {
 	return &Environment{
-		values: make(map[string]any, 0),
+		values:    make(map[string]any, 0),
+		enclosing: nil,
+	}
+}
+
+func NewEnclosedEnvironment(enclosing *Environment) *Environment {
+	return &Environment{
+		values:    make(map[string]any, 0),
+		enclosing: enclosing,
 	}
 }
 
@@ -14,7 +23,24 @@ func (e *Environment) Define(name string, value any) {
 	e.values[name] = value
 }
 
+// Assign updates an existing variable, reporting false if it is undefined.
+func (e *Environment) Assign(name string, value any) bool {
+	if _, ok := e.values[name]; ok {
+		e.values[name] = value
+		return true
+	}
+	if e.enclosing != nil {
+		return e.enclosing.Assign(name, value)
+	}
+	return false
+}
+
 func (e *Environment) Get(name string) (any, bool) {
-	v, ok := e.values[name]
-	return v, ok
+	if v, ok := e.values[name]; ok {
+		return v, true
+	}
+	if e.enclosing != nil {
+		return e.enclosing.Get(name)
+	}
+	return nil, false
 }
@@ -3,3 +3,10 @@ package error_reporters
 type ErrorReporter[E error] interface {
 	ReportError(E)
 }
+
+// Traced is implemented by errors that carry a Lox call stack. Trace returns
+// one line per frame, innermost first.
+type Traced interface {
+	error
+	Trace() []string
+}
@@ -12,6 +12,12 @@ type writeReporter struct {
 
 func (wr *writeReporter) ReportError(err error) {
 	fmt.Fprintln(wr.writable, err.Error())
+
+	if traced, ok := err.(Traced); ok {
+		for _, frame := range traced.Trace() {
+			fmt.Fprintln(wr.writable, "    "+frame)
+		}
+	}
 }
 
 type StdoutReporter struct {
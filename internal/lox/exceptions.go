@@ -0,0 +1,135 @@
+package lox
+
+import "fmt"
+
+// LoxException is the value bound by a catch clause. Thrown values that are
+// not already exceptions are wrapped in one, as are runtime errors raised by
+// the interpreter. Its stack property lists the call stack as printed by
+// the reporters, and frames gives the same frames as maps with function,
+// file and line keys, innermost first.
+type LoxException struct {
+	message string
+	value   any
+	line    int
+	stack   []StackFrame
+}
+
+func (e *LoxException) String() string {
+	return "Error: " + e.message
+}
+
+func (e *LoxException) get(name string) (any, bool) {
+	switch name {
+	case "message":
+		return e.message, true
+	case "value":
+		return e.value, true
+	case "line":
+		return int64(e.line), true
+	case "stack":
+		stack := make([]any, len(e.stack))
+		for idx, frame := range e.stack {
+			stack[idx] = frame.String()
+		}
+		return NewLoxList(stack), true
+	case "frames":
+		frames := make([]any, len(e.stack))
+		for idx, frame := range e.stack {
+			m := NewLoxMap()
+			m.Set("function", frame.Function)
+			m.Set("file", frame.File)
+			m.Set("line", int64(frame.Line))
+			frames[idx] = m
+		}
+		return NewLoxList(frames), true
+	}
+	return nil, false
+}
+
+// thrownError carries a thrown exception up to the nearest enclosing catch.
+type thrownError struct {
+	exception *LoxException
+}
+
+func (e *thrownError) Error() string {
+	return fmt.Sprintf("[line %d] Uncaught error: %s", e.exception.line, e.exception.message)
+}
+
+// StackTrace returns the call stack at the throw, innermost frame first.
+func (e *thrownError) StackTrace() []StackFrame {
+	return e.exception.stack
+}
+
+func (e *thrownError) Trace() []string {
+	return formatTrace(e.exception.stack)
+}
+
+// toException converts errors that scripts may catch into an exception value.
+// Control flow such as exit() and break is not catchable, and neither is
+// cancellation of the script.
+func toException(err error) (*LoxException, bool) {
+	switch err := err.(type) {
+	case *thrownError:
+		return err.exception, true
+	case *RuntimeError:
+		if err.cause != nil {
+			return nil, false
+		}
+		return &LoxException{
+			message: err.message,
+			value:   err.message,
+			line:    err.token.Line,
+			stack:   err.stack,
+		}, true
+	}
+	return nil, false
+}
+
+// StackFrame describes one active call when an error was raised. Line is the
+// line being executed within Function.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f StackFrame) String() string {
+	return fmt.Sprintf("at %s (%s:%d)", f.Function, f.File, f.Line)
+}
+
+type callFrame struct {
+	function string
+	// file and line locate the call expression that entered the frame.
+	file string
+	line int
+}
+
+// stackTrace describes the active calls, innermost first, for an error
+// raised at line of the file currently executing.
+func (i *Interpreter) stackTrace(line int) []StackFrame {
+	file := i.file
+	trace := make([]StackFrame, 0, len(i.frames)+1)
+	for idx := len(i.frames) - 1; idx >= 0; idx-- {
+		trace = append(trace, StackFrame{Function: i.frames[idx].function, File: file, Line: line})
+		file, line = i.frames[idx].file, i.frames[idx].line
+	}
+	return append(trace, StackFrame{Function: "<script>", File: file, Line: line})
+}
+
+// traceEdge is the number of frames kept at each end of a long printed
+// trace, such as one produced by runaway recursion.
+const traceEdge = 20
+
+func formatTrace(stack []StackFrame) []string {
+	trace := make([]string, 0, len(stack))
+	for idx, frame := range stack {
+		if len(stack) > 2*traceEdge+1 && idx >= traceEdge && idx < len(stack)-traceEdge {
+			if idx == traceEdge {
+				trace = append(trace, fmt.Sprintf("... %d more frames", len(stack)-2*traceEdge))
+			}
+			continue
+		}
+		trace = append(trace, frame.String())
+	}
+	return trace
+}
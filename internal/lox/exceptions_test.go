@@ -0,0 +1,138 @@
+package lox
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTryCatchFinally(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			"throw and catch",
+			`try { log.push("try"); throw "bad"; log.push("unreached"); } catch (e) { log.push(e.message); log.push(e.value); log.push(e.line); } finally { log.push("finally"); }`,
+			"[try, bad, bad, 2, finally]",
+		},
+		{
+			"thrown values are kept",
+			`try { throw {"code": 3}; } catch (e) { log.push(e.value["code"]); log.push(e.message); }`,
+			"[3, {code: 3}]",
+		},
+		{
+			"runtime errors are catchable",
+			`try { nil + 1; } catch (e) { log.push(e.message); } try { missing; } catch (e) { log.push(e.message); }`,
+			"[Operands must be two numbers or two strings., Undefined variable 'missing'.]",
+		},
+		{
+			"finally runs on return",
+			`var f = fun () { try { return 1; } finally { log.push("finally"); } }; log.push(f());`,
+			"[finally, 1]",
+		},
+		{
+			"finally runs before an outer catch",
+			`try { try { throw 1; } finally { log.push("inner"); } } catch (e) { log.push(e.value); }`,
+			"[inner, 1]",
+		},
+		{
+			"finally runs on break",
+			`for (x in [1, 2, 3]) { try { break; } finally { log.push(x); } }`,
+			"[1]",
+		},
+		{
+			"rethrow",
+			`try { try { throw "a"; } catch (e) { throw e; } } catch (e) { log.push(e.value); }`,
+			"[a]",
+		},
+	}
+
+	for _, test := range tests {
+		i := NewInterpreter()
+		if err := run(t, i, "var log = [];\n"+test.source); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := stringify(eval(t, i, "log")); got != test.want {
+			t.Errorf("%s: log = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestUncaughtThrow(t *testing.T) {
+	i := NewInterpreter()
+	err := run(t, i, `var f = fun () { throw "boom"; };`+"\nf();")
+
+	var thrown *thrownError
+	if !errors.As(err, &thrown) {
+		t.Fatalf("error = %v, want a thrown error", err)
+	}
+	if thrown.exception.message != "boom" || thrown.exception.line != 1 {
+		t.Errorf("exception = %q on line %d, want \"boom\" on line 1", thrown.exception.message, thrown.exception.line)
+	}
+	if got := len(thrown.StackTrace()); got != 2 {
+		t.Errorf("stack has %d frames, want 2", got)
+	}
+}
+
+func TestExitIsNotCatchable(t *testing.T) {
+	i := NewInterpreter()
+	err := run(t, i, `try { exit(3); } catch (e) { print "caught"; }`)
+
+	var exit *ExitError
+	if !errors.As(err, &exit) || exit.Code != 3 {
+		t.Errorf("error = %v, want exit status 3", err)
+	}
+}
+
+func TestExceptionFrames(t *testing.T) {
+	i := NewInterpreter()
+	i.SetFile("test.lox")
+	err := run(t, i, `
+var inner = fun () { throw "x"; };
+var outer = fun () { inner(); };
+var e;
+try { outer(); } catch (caught) { e = caught; }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stringify(eval(t, i, "e.stack")); got != "[at inner (test.lox:2), at outer (test.lox:3), at <script> (test.lox:5)]" {
+		t.Errorf("e.stack = %s", got)
+	}
+	if got := eval(t, i, `e.frames[2]["function"] + ":" + e.frames[2]["file"]`); got != "<script>:test.lox" {
+		t.Errorf("outermost frame = %v, want <script>:test.lox", got)
+	}
+	if got := eval(t, i, `e.frames[0]["line"]`); got != int64(2) {
+		t.Errorf("innermost frame line = %v, want 2", got)
+	}
+}
+
+func TestStackFramesNameFunctions(t *testing.T) {
+	i := NewInterpreter()
+	i.SetFile("test.lox")
+	err := run(t, i, `
+var boom = fun () { throw "done"; };
+var countdown = fun (n) {
+  return n == 0 ? boom() : countdown(n - 1);
+};
+var handler;
+handler = (x) => countdown(x);
+var callbacks = [fun () { handler(2); }];
+var e;
+try { callbacks[0](); } catch (caught) { e = caught; }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[boom, countdown, countdown, countdown, handler, <lambda>, <script>]"
+	if got := stringify(eval(t, i, `e.frames.map((frame) => frame["function"])`)); got != want {
+		t.Errorf("frame functions = %s, want %s", got, want)
+	}
+	if got := stringify(eval(t, i, `e.stack[1]`)); got != "at countdown (test.lox:4)" {
+		t.Errorf("innermost frame = %s", got)
+	}
+}
@@ -0,0 +1,167 @@
+package lox
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileSystem is the capability through which scripts access files. Hosts
+// choose an implementation with Lox.SetFileSystem; a nil FileSystem disables
+// file access entirely.
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte) error
+	AppendFile(name string, data []byte) error
+	ReadDir(name string) ([]string, error)
+	Exists(name string) (bool, error)
+}
+
+// OSFileSystem gives scripts unrestricted access to the host filesystem.
+type OSFileSystem struct{}
+
+func (OSFileSystem) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OSFileSystem) WriteFile(name string, data []byte) error {
+	return os.WriteFile(name, data, 0o644)
+}
+
+func (OSFileSystem) AppendFile(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return errors.Join(err, f.Close())
+}
+
+func (OSFileSystem) ReadDir(name string) ([]string, error) {
+	return entryNames(os.ReadDir(name))
+}
+
+func (OSFileSystem) Exists(name string) (bool, error) {
+	return exists(os.Stat(name))
+}
+
+// RootFileSystem restricts scripts to the directory tree below a root.
+// Paths are resolved relative to the root and may not escape it, including
+// through symbolic links.
+type RootFileSystem struct {
+	root *os.Root
+}
+
+func NewRootFileSystem(dir string) (*RootFileSystem, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &RootFileSystem{root: root}, nil
+}
+
+func (r *RootFileSystem) ReadFile(name string) ([]byte, error) {
+	f, err := r.root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	return data, errors.Join(err, f.Close())
+}
+
+func (r *RootFileSystem) WriteFile(name string, data []byte) error {
+	return r.writeFile(name, data, os.O_TRUNC|os.O_CREATE|os.O_WRONLY)
+}
+
+func (r *RootFileSystem) AppendFile(name string, data []byte) error {
+	return r.writeFile(name, data, os.O_APPEND|os.O_CREATE|os.O_WRONLY)
+}
+
+func (r *RootFileSystem) writeFile(name string, data []byte, flag int) error {
+	f, err := r.root.OpenFile(name, flag, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return errors.Join(err, f.Close())
+}
+
+func (r *RootFileSystem) ReadDir(name string) ([]string, error) {
+	return entryNames(fs.ReadDir(r.root.FS(), name))
+}
+
+func (r *RootFileSystem) Exists(name string) (bool, error) {
+	return exists(r.root.Stat(name))
+}
+
+func entryNames(entries []fs.DirEntry, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(entries))
+	for idx, entry := range entries {
+		names[idx] = entry.Name()
+	}
+	return names, nil
+}
+
+func exists(_ os.FileInfo, err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// ReadOnlyFileSystem exposes an io/fs.FS, such as an embed.FS, to the file
+// natives. Writes fail with fs.ErrPermission.
+type ReadOnlyFileSystem struct {
+	fsys fs.FS
+}
+
+func NewReadOnlyFileSystem(fsys fs.FS) *ReadOnlyFileSystem {
+	return &ReadOnlyFileSystem{fsys: fsys}
+}
+
+func (r *ReadOnlyFileSystem) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(r.fsys, name)
+}
+
+func (r *ReadOnlyFileSystem) WriteFile(name string, data []byte) error {
+	return &fs.PathError{Op: "write", Path: name, Err: fs.ErrPermission}
+}
+
+func (r *ReadOnlyFileSystem) AppendFile(name string, data []byte) error {
+	return &fs.PathError{Op: "append", Path: name, Err: fs.ErrPermission}
+}
+
+func (r *ReadOnlyFileSystem) ReadDir(name string) ([]string, error) {
+	return entryNames(fs.ReadDir(r.fsys, name))
+}
+
+func (r *ReadOnlyFileSystem) Exists(name string) (bool, error) {
+	return exists(fs.Stat(r.fsys, name))
+}
+
+// HostFS is an fs.FS over the host filesystem. Unlike os.DirFS it accepts
+// any path the operating system does, including absolute paths, so command
+// line arguments can be used as given. It deliberately does not check names
+// with fs.ValidPath: names are slash-separated or OS paths, which may be
+// absolute or contain "..", and are confined only by the operating system.
+type HostFS struct{}
+
+func (HostFS) Open(name string) (fs.File, error) {
+	return os.Open(filepath.FromSlash(name))
+}
+
+func (HostFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(filepath.FromSlash(name))
+}
+
+func (HostFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(filepath.FromSlash(name))
+}
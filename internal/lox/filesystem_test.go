@@ -0,0 +1,166 @@
+package lox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// newSandbox creates a directory tree with a sandbox directory, a secret
+// file beside it and a symbolic link inside the sandbox that points to the
+// secret.
+func newSandbox(t *testing.T) (dir, sandbox string) {
+	t.Helper()
+
+	dir = t.TempDir()
+	sandbox = filepath.Join(dir, "sandbox")
+	files := map[string]string{
+		filepath.Join(dir, "secret.txt"):       "secret",
+		filepath.Join(sandbox, "inside.txt"):   "inside",
+		filepath.Join(sandbox, "sub", "x.txt"): "x",
+	}
+	for name, data := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(sandbox, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(dir, filepath.Join(sandbox, "parent")); err != nil {
+		t.Fatal(err)
+	}
+	return dir, sandbox
+}
+
+func TestRootFileSystemConfinesScripts(t *testing.T) {
+	dir, sandbox := newSandbox(t)
+	root, err := NewRootFileSystem(sandbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := NewInterpreter()
+	i.SetFileSystem(root)
+
+	if got := eval(t, i, `readFile("inside.txt") + readFile("sub/../sub/x.txt")`); got != "insidex" {
+		t.Errorf("reading inside the root = %v, want insidex", got)
+	}
+	if err := run(t, i, `writeFile("out.txt", "a"); appendFile("out.txt", "b");`); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(sandbox, "out.txt")); string(data) != "ab" {
+		t.Errorf("out.txt = %q, want ab", data)
+	}
+
+	secret := filepath.ToSlash(filepath.Join(dir, "secret.txt"))
+	escapes := []string{
+		`readFile("../secret.txt")`,
+		`readFile("sub/../../secret.txt")`,
+		fmt.Sprintf(`readFile(%q)`, secret),
+		`readFile("link.txt")`,
+		`readFile("parent/secret.txt")`,
+		`listDir("..")`,
+		`listDir("parent")`,
+		`writeFile("../escape.txt", "x")`,
+		`appendFile("parent/escape.txt", "x")`,
+		fmt.Sprintf(`writeFile(%q, "x")`, filepath.ToSlash(filepath.Join(dir, "escape.txt"))),
+	}
+	for _, source := range escapes {
+		if err := run(t, i, source+";"); err == nil {
+			t.Errorf("%s succeeded outside the root", source)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.txt")); err == nil {
+		t.Error("a write escaped the root")
+	}
+
+	for _, source := range []string{`exists("../secret.txt")`, `exists("link.txt")`} {
+		if err := run(t, i, "var result = "+source+";"); err == nil {
+			if got, _ := i.env.Get("result"); got != false {
+				t.Errorf("%s = %v outside the root", source, got)
+			}
+		}
+	}
+}
+
+func TestNilFileSystemDisablesFileAccess(t *testing.T) {
+	i := NewInterpreter()
+	i.SetFileSystem(nil)
+
+	natives := []string{
+		`readFile("a.txt")`,
+		`writeFile("a.txt", "x")`,
+		`appendFile("a.txt", "x")`,
+		`listDir(".")`,
+		`exists("a.txt")`,
+	}
+	for _, source := range natives {
+		err := run(t, i, source+";")
+		if err == nil || !strings.Contains(err.Error(), "File access is disabled.") {
+			t.Errorf("%s error = %v, want file access disabled", source, err)
+		}
+	}
+}
+
+func TestReadOnlyFileSystem(t *testing.T) {
+	i := NewInterpreter()
+	i.SetFileSystem(NewReadOnlyFileSystem(fstest.MapFS{
+		"data/a.txt": {Data: []byte("a")},
+		"data/b.txt": {Data: []byte("b")},
+	}))
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`readFile("data/a.txt")`, "a"},
+		{`listDir("data")`, "[a.txt, b.txt]"},
+		{`exists("data/b.txt")`, "true"},
+		{`exists("data/c.txt")`, "false"},
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+
+	rejected := []string{
+		`writeFile("data/a.txt", "x")`,
+		`appendFile("data/new.txt", "x")`,
+		`readFile("../data/a.txt")`,
+		`readFile("/data/a.txt")`,
+	}
+	for _, source := range rejected {
+		if err := run(t, i, source+";"); err == nil {
+			t.Errorf("%s succeeded on a read-only filesystem", source)
+		}
+	}
+	if got := eval(t, i, `readFile("data/a.txt")`); got != "a" {
+		t.Errorf("data/a.txt = %v after rejected writes, want a", got)
+	}
+}
+
+func TestReadLineSharesThePromptReader(t *testing.T) {
+	l := NewLox()
+	l.SetStdin(strings.NewReader("var name = readLine();\nAnn\nvar rest = readLine();\n\n"))
+
+	if status := l.RunPrompt(); status != 0 {
+		t.Fatalf("RunPrompt status = %d, want 0", status)
+	}
+
+	// readLine() reads the line after the statement that calls it, and the
+	// prompt continues after that line.
+	if got, _ := l.interpreter.env.Get("name"); got != "Ann" {
+		t.Errorf("name = %v, want Ann", got)
+	}
+	if got, _ := l.interpreter.env.Get("rest"); got != "" {
+		t.Errorf("rest = %q, want the empty line", got)
+	}
+}
@@ -0,0 +1,68 @@
+package lox
+
+import "github.com/LucDeCaf/go-lox/internal/lox/ast"
+
+// maxCallDepth limits nested calls, so that runaway recursion is reported
+// as a runtime error instead of exhausting the Go stack.
+const maxCallDepth = 10000
+
+// LoxFunction is a function written in Lox. It closes over the environment
+// it was created in, so it can read and assign variables of enclosing
+// scopes even after they have been exited.
+type LoxFunction struct {
+	declaration *ast.FunctionExpr
+	closure     *Environment
+	// name is the function's declared name or, for a function expression
+	// assigned straight to a variable, the variable's name. It is empty for
+	// other anonymous functions.
+	name string
+	// file is the script the function was defined in, reported in stack
+	// traces for errors raised in its body.
+	file string
+}
+
+func (f *LoxFunction) Name() string {
+	if f.name == "" {
+		return "<lambda>"
+	}
+	return f.name
+}
+
+func (f *LoxFunction) Arity() int {
+	return len(f.declaration.Params)
+}
+
+func (f *LoxFunction) Call(i *Interpreter, args []any) (any, error) {
+	env := NewEnclosedEnvironment(f.closure)
+	for idx, param := range f.declaration.Params {
+		env.Define(param.Lexeme, args[idx])
+	}
+
+	previousFile := i.file
+	i.file = f.file
+	defer func() { i.file = previousFile }()
+
+	err := i.executeBlock(f.declaration.Body, env)
+	if ret, ok := err.(*returnValue); ok {
+		return ret.value, nil
+	}
+	return nil, err
+}
+
+func (f *LoxFunction) String() string {
+	if f.name == "" {
+		return "<fn>"
+	}
+	return "<fn " + f.name + ">"
+}
+
+// nameFunction names an anonymous function expression after the variable
+// it is bound to, so that stack traces can tell functions apart.
+func nameFunction(expr ast.Expr, value any, name string) {
+	if _, ok := expr.(*ast.FunctionExpr); !ok {
+		return
+	}
+	if function, ok := value.(*LoxFunction); ok && function.name == "" {
+		function.name = name
+	}
+}
@@ -0,0 +1,134 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFunctionExpressions(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`double(4)`, "8"},
+		{`add(1, 2)`, "3"},
+		{`seven()`, "7"},
+		{`(fun (x) { return x; })(5)`, "5"},
+		{`fun () {}()`, "nil"},
+		{`fun () { return 1; return 2; }()`, "1"},
+		{`sorted`, "[1, 2, 3]"},
+		{`[1, 2, 3].map((x) => x * x)`, "[1, 4, 9]"},
+		{`[1, 2, 3, 4].filter(fun (x) { return x % 2 == 0; })`, "[2, 4]"},
+		{`((x) => (y) => x + y)(1)(2)`, "3"},
+		{`double`, "<fn double>"},
+		{`(1 + 2) * 3`, "9"},
+	}
+
+	i := NewInterpreter()
+	err := run(t, i, `
+var double = (x) => x * 2;
+var add = fun (a, b) { return a + b; };
+var seven = () => 7;
+var sorted = [3, 1, 2];
+sorted.sort((a, b) => a - b);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestClosures(t *testing.T) {
+	i := NewInterpreter()
+	err := run(t, i, `
+var makeCounter = fun () {
+  var n = 0;
+  return fun () { n += 1; return n; };
+};
+var a = makeCounter();
+var b = makeCounter();
+a();
+a();
+b();
+var handlers = [];
+for (x in [1, 2, 3]) {
+  handlers.push(() => x * 10);
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := eval(t, i, "[a(), b()]"); stringify(got) != "[3, 2]" {
+		t.Errorf("counters = %s, want [3, 2]", stringify(got))
+	}
+	if got := eval(t, i, "handlers.map((h) => h())"); stringify(got) != "[10, 20, 30]" {
+		t.Errorf("loop closures = %s, want [10, 20, 30]", stringify(got))
+	}
+}
+
+func TestFunctionErrors(t *testing.T) {
+	syntax := []string{
+		"return 1;",
+		"for (x in [1]) { var f = fun () { break; }; }",
+		"var f = (x) => return x;",
+	}
+	for _, source := range syntax {
+		tokens, ok := NewScanner().scanTokens(source)
+		if ok {
+			if _, ok = NewParser().parse(tokens); ok {
+				t.Errorf("%s parsed, want a syntax error", source)
+			}
+		}
+	}
+
+	runtime := []struct {
+		source string
+		want   string
+	}{
+		{"fun (x) {}();", "Expected 1 arguments but got 0."},
+		{"var f = fun () { return f(); }; f();", "Stack overflow."},
+	}
+	for _, test := range runtime {
+		err := run(t, NewInterpreter(), test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s error = %v, want %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestNamedFunctionExpressions(t *testing.T) {
+	i := NewInterpreter()
+	i.SetFile("test.lox")
+	err := run(t, i, `
+var f = fun fact(n) { return n < 2 ? 1 : n * fact(n - 1); };
+var e;
+try { fun boom() { throw "x"; }(); } catch (caught) { e = caught; }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`f(5)`, "120"},
+		{`f`, "<fn fact>"},
+		{`[fun () {}][0]`, "<fn>"},
+		{`e.frames[0]["function"]`, "boom"},
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+
+	if got := parseExpression(t, "fun add(a, b) { return a + b; }"); got != "(fun add (a b))" {
+		t.Errorf("named function parsed as %s", got)
+	}
+}
@@ -1,28 +1,156 @@
 package lox
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"github.com/LucDeCaf/go-lox/internal/lox/ast"
+	"math/big"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Interpreter struct {
-	env    *Environment
-	errors []error
+	// globals holds the natives. Each file runs in its own environment
+	// enclosed by globals.
+	globals *Environment
+	env     *Environment
+	modules *moduleLoader
+	args    []string
+	frames  []callFrame
+	// file is the name of the script currently executing, used in stack
+	// traces.
+	file  string
+	rand  *rand.Rand
+	stdin *bufio.Reader
+	fs    FileSystem
+	clock Clock
+	// lookupEnv reads the environment variables returned by env(); nil
+	// disables env().
+	lookupEnv func(name string) (string, bool)
+	// ctx cancels long-running scripts; it is checked by loops and sleep().
+	ctx context.Context
+	// strictMath makes division by zero a runtime error instead of
+	// producing an infinity or NaN.
+	strictMath bool
 }
 
 type RuntimeError struct {
+	token   *ast.Token
 	message string
+	stack   []StackFrame
+	// cause is set when the error reports a host condition rather than a
+	// fault in the script; such errors cannot be caught by scripts.
+	cause error
 }
 
+// Error returns the message headed by the file and line the error was
+// raised at. The file is known once the error has its stack trace.
 func (e *RuntimeError) Error() string {
-	return "RuntimeError: " + e.message
+	if len(e.stack) > 0 {
+		return fmt.Sprintf("[%s:%d] RuntimeError: %s", e.stack[0].File, e.token.Line, e.message)
+	}
+	return fmt.Sprintf("[line %d] RuntimeError: %s", e.token.Line, e.message)
+}
+
+// StackTrace returns the call stack at the point the error was raised,
+// innermost frame first.
+func (e *RuntimeError) StackTrace() []StackFrame {
+	return e.stack
+}
+
+// Unwrap returns the host error behind the runtime error, such as the
+// context error when a script is cancelled.
+func (e *RuntimeError) Unwrap() error {
+	return e.cause
+}
+
+func (e *RuntimeError) Trace() []string {
+	return formatTrace(e.stack)
+}
+
+// shortCircuit is returned by an optional property access on nil. It
+// unwinds the rest of the enclosing optional chain, which evaluates to nil.
+type shortCircuit struct{}
+
+func (s *shortCircuit) Error() string {
+	return "optional chain short-circuited"
+}
+
+// ExitError is returned from Interpret when a script calls exit(code).
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// loopControl unwinds execution from a break or continue statement to the
+// innermost enclosing loop.
+type loopControl struct {
+	keyword *ast.Token
+}
+
+func (c *loopControl) Error() string {
+	return fmt.Sprintf("[line %d] RuntimeError: '%s' outside of a loop.", c.keyword.Line, c.keyword.Lexeme)
+}
+
+// returnValue unwinds execution from a return statement to the call of the
+// enclosing function.
+type returnValue struct {
+	keyword *ast.Token
+	value   any
+}
+
+func (r *returnValue) Error() string {
+	return fmt.Sprintf("[line %d] RuntimeError: 'return' outside of a function.", r.keyword.Line)
 }
 
 func NewInterpreter() *Interpreter {
-	return &Interpreter{
-		env:    NewEnvironment(),
-		errors: []error{},
+	globals := NewEnvironment()
+	i := &Interpreter{
+		globals: globals,
+		env:     NewEnclosedEnvironment(globals),
+		modules: newModuleLoader(),
+		args:    []string{},
+		frames:  []callFrame{},
+		file:    "<stdin>",
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		stdin:   bufio.NewReader(os.Stdin),
+		fs:      OSFileSystem{},
+		clock:   SystemClock{},
+		ctx:     context.Background(),
 	}
+	i.lookupEnv = os.LookupEnv
+	i.defineNatives()
+	return i
+}
+
+// SetContext sets the context whose cancellation stops the running script.
+func (i *Interpreter) SetContext(ctx context.Context) {
+	i.ctx = ctx
+}
+
+// SetStrictMath selects how floating-point division by zero behaves. By
+// default it follows IEEE 754, so 1 / 0 is Infinity and 0 / 0 is NaN; in
+// strict mode it is a runtime error. Integer division by zero is always an
+// error, since integers have no infinity.
+func (i *Interpreter) SetStrictMath(strict bool) {
+	i.strictMath = strict
+}
+
+// SetFile sets the script name reported in stack traces.
+func (i *Interpreter) SetFile(file string) {
+	i.file = file
+}
+
+// SetArgs sets the command-line arguments returned by the args() native.
+func (i *Interpreter) SetArgs(args []string) {
+	i.args = args
 }
 
 func (i *Interpreter) VisitLiteralExpr(l *ast.LiteralExpr) any {
@@ -30,91 +158,75 @@ func (i *Interpreter) VisitLiteralExpr(l *ast.LiteralExpr) any {
 }
 
 func (i *Interpreter) VisitGroupingExpr(g *ast.GroupingExpr) any {
-	return i.evaluate(g.Expression)
+	return g.Expression.Accept(i)
 }
 
 func (i *Interpreter) VisitBinaryExpr(b *ast.BinaryExpr) any {
-	left := i.evaluate(b.Left)
-	right := i.evaluate(b.Right)
-
-	switch b.Operator.Type {
-	case ast.MINUS:
-		left, right, ok := extractFloats(left, right)
-		if !ok {
-			return nil
-		}
-
-		return left - right
-
-	case ast.STAR:
-		left, right, ok := extractFloats(left, right)
-		if !ok {
-			return nil
-		}
+	left, err := i.evaluate(b.Left)
+	if err != nil {
+		return err
+	}
+	right, err := i.evaluate(b.Right)
+	if err != nil {
+		return err
+	}
 
-		return left * right
+	return i.binary(b.Operator, left, right)
+}
 
-	case ast.SLASH:
-		left, right, ok := extractFloats(left, right)
-		if !ok {
-			return nil
+// binary applies a binary operator to evaluated operands. It returns a
+// *RuntimeError if the operands are invalid.
+func (i *Interpreter) binary(operator *ast.Token, left, right any) any {
+	switch operator.Type {
+	case ast.MINUS, ast.STAR, ast.SLASH, ast.TILDE_SLASH, ast.PERCENT, ast.STAR_STAR:
+		if i.strictMath && dividesByZero(operator.Type, left, right) {
+			return &RuntimeError{token: operator, message: "Division by zero."}
 		}
+		return arithmetic(operator, left, right)
 
-		return left / right
+	case ast.AMPERSAND, ast.PIPE, ast.CARET, ast.LESS_LESS, ast.GREATER_GREATER:
+		return bitwise(operator, left, right)
 
 	case ast.PLUS:
-		switch left := left.(type) {
-		case float64:
-			right, ok := right.(float64)
-			if !ok {
-				return nil
-			}
-			return left + right
-		case string:
-			right, ok := right.(string)
-			if !ok {
-				return nil
+		if isNumber(left) && isNumber(right) {
+			return arithmetic(operator, left, right)
+		}
+		if left, ok := left.(string); ok {
+			if right, ok := right.(string); ok {
+				return left + right
 			}
-			return left + right
 		}
 
-	case ast.GREATER:
-		left, right, ok := extractFloats(left, right)
-		if !ok {
-			return nil
+		return &RuntimeError{
+			token:   operator,
+			message: "Operands must be two numbers or two strings.",
 		}
 
-		return left > right
-
-	case ast.GREATER_EQUAL:
-		left, right, ok := extractFloats(left, right)
-		if !ok {
-			return nil
+	case ast.GREATER, ast.GREATER_EQUAL, ast.LESS, ast.LESS_EQUAL:
+		if !isNumber(left) || !isNumber(right) {
+			return operandsError(operator)
 		}
 
-		return left >= right
-
-	case ast.LESS:
-		left, right, ok := extractFloats(left, right)
+		// Comparisons involving NaN are always false.
+		c, ok := compareNumbers(left, right)
 		if !ok {
-			return nil
+			return false
 		}
-
-		return left < right
-
-	case ast.LESS_EQUAL:
-		left, right, ok := extractFloats(left, right)
-		if !ok {
-			return nil
+		switch operator.Type {
+		case ast.GREATER:
+			return c > 0
+		case ast.GREATER_EQUAL:
+			return c >= 0
+		case ast.LESS:
+			return c < 0
 		}
-
-		return left <= right
+		return c <= 0
 
 	case ast.BANG_EQUAL:
-		return left != right
+		return !isEqual(left, right)
 
 	case ast.EQUAL_EQUAL:
-		return left == right
+		return isEqual(left, right)
 	}
 
 	// Unreachable
@@ -122,19 +234,36 @@ func (i *Interpreter) VisitBinaryExpr(b *ast.BinaryExpr) any {
 }
 
 func (i *Interpreter) VisitUnaryExpr(u *ast.UnaryExpr) any {
-	right := i.evaluate(u.Right)
+	right, err := i.evaluate(u.Right)
+	if err != nil {
+		return err
+	}
 
 	switch u.Operator.Type {
 	case ast.MINUS:
-		right, ok := right.(float64)
+		n, ok := negate(right)
 		if !ok {
-			return nil
+			return &RuntimeError{
+				token:   u.Operator,
+				message: "Operand must be a number.",
+			}
 		}
 
-		return -right
+		return n
 
 	case ast.BANG:
 		return !isTruthy(right)
+
+	case ast.TILDE:
+		n, ok := toBig(right)
+		if !ok {
+			return &RuntimeError{
+				token:   u.Operator,
+				message: "Operand must be an integer.",
+			}
+		}
+
+		return normalize(new(big.Int).Not(n))
 	}
 
 	// Unreachable
@@ -145,47 +274,585 @@ func (i *Interpreter) VisitVariableExpr(u *ast.VariableExpr) any {
 	v, ok := i.env.Get(u.Name.Lexeme)
 	if !ok {
 		return &RuntimeError{
-			message: "Undefined variable '" + u.Name.Lexeme + "'",
+			token:   u.Name,
+			message: "Undefined variable '" + u.Name.Lexeme + "'.",
 		}
 	}
 	return v
 }
 
+func (i *Interpreter) VisitCallExpr(c *ast.CallExpr) any {
+	callee, err := i.evaluate(c.Callee)
+	if err != nil {
+		return err
+	}
+
+	args := make([]any, 0, len(c.Arguments))
+	for _, arg := range c.Arguments {
+		value, err := i.evaluate(arg)
+		if err != nil {
+			return err
+		}
+		args = append(args, value)
+	}
+
+	function, ok := callee.(LoxCallable)
+	if !ok {
+		return &RuntimeError{
+			token:   c.Paren,
+			message: "Can only call functions and classes.",
+		}
+	}
+
+	result, err := i.callAt(function, args, c.Paren.Line)
+	if err != nil {
+		return atToken(err, c.Paren)
+	}
+	return result
+}
+
+func (i *Interpreter) VisitAssignExpr(e *ast.AssignExpr) any {
+	value, err := i.evaluate(e.Value)
+	if err != nil {
+		return err
+	}
+
+	nameFunction(e.Value, value, e.Name.Lexeme)
+	if !i.env.Assign(e.Name.Lexeme, value) {
+		return &RuntimeError{
+			token:   e.Name,
+			message: "Undefined variable '" + e.Name.Lexeme + "'.",
+		}
+	}
+	return value
+}
+
+// compoundOperators maps compound assignment, increment and decrement
+// operators to the binary operator they apply.
+var compoundOperators = map[ast.TokenType]ast.TokenType{
+	ast.PLUS_EQUAL:    ast.PLUS,
+	ast.MINUS_EQUAL:   ast.MINUS,
+	ast.STAR_EQUAL:    ast.STAR,
+	ast.SLASH_EQUAL:   ast.SLASH,
+	ast.PERCENT_EQUAL: ast.PERCENT,
+	ast.PLUS_PLUS:     ast.PLUS,
+	ast.MINUS_MINUS:   ast.MINUS,
+}
+
+func (i *Interpreter) VisitCompoundAssignExpr(e *ast.CompoundAssignExpr) any {
+	_, value, err := i.update(e.Target, func(old any) any {
+		value, err := i.evaluate(e.Value)
+		if err != nil {
+			return err
+		}
+		return i.binary(compoundOperator(e.Operator), old, value)
+	})
+	if err != nil {
+		return err
+	}
+	return value
+}
+
+// VisitUpdateExpr evaluates ++ and --. The prefix forms yield the updated
+// value and the postfix forms the original one.
+func (i *Interpreter) VisitUpdateExpr(e *ast.UpdateExpr) any {
+	old, value, err := i.update(e.Target, func(old any) any {
+		if !isNumber(old) {
+			return &RuntimeError{
+				token:   e.Operator,
+				message: "Operand of '" + e.Operator.Lexeme + "' must be a number.",
+			}
+		}
+		return i.binary(compoundOperator(e.Operator), old, int64(1))
+	})
+	if err != nil {
+		return err
+	}
+	if e.Prefix {
+		return value
+	}
+	return old
+}
+
+// update replaces the value of target, a variable or index expression, with
+// the result of fn, which may be an error. The target's object and index are
+// evaluated only once.
+func (i *Interpreter) update(target ast.Expr, fn func(old any) any) (old, value any, err error) {
+	switch target := target.(type) {
+	case *ast.VariableExpr:
+		old, ok := i.env.Get(target.Name.Lexeme)
+		if !ok {
+			return nil, nil, &RuntimeError{
+				token:   target.Name,
+				message: "Undefined variable '" + target.Name.Lexeme + "'.",
+			}
+		}
+		value := fn(old)
+		if err, ok := value.(error); ok {
+			return nil, nil, err
+		}
+		i.env.Assign(target.Name.Lexeme, value)
+		return old, value, nil
+
+	case *ast.IndexExpr:
+		object, err := i.evaluate(target.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		index, err := i.evaluate(target.Index)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		container, ok := object.(indexable)
+		if !ok {
+			return nil, nil, &RuntimeError{
+				token:   target.Bracket,
+				message: "Only lists and maps can be indexed.",
+			}
+		}
+
+		old, err := container.Get(index)
+		if err != nil {
+			return nil, nil, atToken(err, target.Bracket)
+		}
+		value := fn(old)
+		if err, ok := value.(error); ok {
+			return nil, nil, err
+		}
+		if err := container.Set(index, value); err != nil {
+			return nil, nil, atToken(err, target.Bracket)
+		}
+		return old, value, nil
+	}
+
+	// Unreachable: the parser only produces variable and index targets.
+	return nil, nil, nil
+}
+
+// compoundOperator returns the binary operator applied by a compound
+// assignment, increment or decrement operator.
+func compoundOperator(operator *ast.Token) *ast.Token {
+	token := ast.NewToken(compoundOperators[operator.Type], operator.Lexeme, nil, operator.Line)
+	return &token
+}
+
+func (i *Interpreter) VisitListExpr(e *ast.ListExpr) any {
+	elements := make([]any, 0, len(e.Elements))
+	for _, element := range e.Elements {
+		value, err := i.evaluate(element)
+		if err != nil {
+			return err
+		}
+		elements = append(elements, value)
+	}
+	return NewLoxList(elements)
+}
+
+func (i *Interpreter) VisitMapExpr(e *ast.MapExpr) any {
+	m := NewLoxMap()
+	for idx, keyExpr := range e.Keys {
+		key, err := i.evaluate(keyExpr)
+		if err != nil {
+			return err
+		}
+		value, err := i.evaluate(e.Values[idx])
+		if err != nil {
+			return err
+		}
+		if err := m.Set(key, value); err != nil {
+			return atToken(err, e.Brace)
+		}
+	}
+	return m
+}
+
+func (i *Interpreter) VisitIndexExpr(e *ast.IndexExpr) any {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return err
+	}
+	index, err := i.evaluate(e.Index)
+	if err != nil {
+		return err
+	}
+
+	container, ok := object.(indexable)
+	if !ok {
+		return &RuntimeError{
+			token:   e.Bracket,
+			message: "Only lists and maps can be indexed.",
+		}
+	}
+
+	value, err := container.Get(index)
+	if err != nil {
+		return atToken(err, e.Bracket)
+	}
+	return value
+}
+
+func (i *Interpreter) VisitIndexSetExpr(e *ast.IndexSetExpr) any {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return err
+	}
+	index, err := i.evaluate(e.Index)
+	if err != nil {
+		return err
+	}
+	value, err := i.evaluate(e.Value)
+	if err != nil {
+		return err
+	}
+
+	container, ok := object.(indexable)
+	if !ok {
+		return &RuntimeError{
+			token:   e.Bracket,
+			message: "Only lists and maps can be indexed.",
+		}
+	}
+
+	if err := container.Set(index, value); err != nil {
+		return atToken(err, e.Bracket)
+	}
+	return value
+}
+
+func (i *Interpreter) VisitGetExpr(e *ast.GetExpr) any {
+	object, err := i.evaluate(e.Object)
+	if err != nil {
+		return err
+	}
+
+	if object == nil && e.Optional {
+		return &shortCircuit{}
+	}
+
+	if value, ok := property(object, e.Name.Lexeme); ok {
+		return value
+	}
+
+	return &RuntimeError{
+		token:   e.Name,
+		message: "Undefined property '" + e.Name.Lexeme + "'.",
+	}
+}
+
+func (i *Interpreter) VisitOptionalChainExpr(e *ast.OptionalChainExpr) any {
+	value, err := i.evaluate(e.Expression)
+	if _, ok := err.(*shortCircuit); ok {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return value
+}
+
+func (i *Interpreter) VisitConditionalExpr(e *ast.ConditionalExpr) any {
+	condition, err := i.evaluate(e.Condition)
+	if err != nil {
+		return err
+	}
+
+	if isTruthy(condition) {
+		return e.ThenBranch.Accept(i)
+	}
+	return e.ElseBranch.Accept(i)
+}
+
+// VisitLogicalExpr evaluates a ?? b, which yields b only when a is nil.
+func (i *Interpreter) VisitLogicalExpr(e *ast.LogicalExpr) any {
+	left, err := i.evaluate(e.Left)
+	if err != nil {
+		return err
+	}
+
+	if left != nil {
+		return left
+	}
+	return e.Right.Accept(i)
+}
+
+// VisitFunctionExpr creates a closure over the current environment. A
+// function written with a name can refer to itself by that name.
+func (i *Interpreter) VisitFunctionExpr(e *ast.FunctionExpr) any {
+	if e.Name == nil {
+		return &LoxFunction{declaration: e, closure: i.env, file: i.file}
+	}
+	closure := NewEnclosedEnvironment(i.env)
+	function := &LoxFunction{declaration: e, closure: closure, file: i.file, name: e.Name.Lexeme}
+	closure.Define(e.Name.Lexeme, function)
+	return function
+}
+
+// property looks up a property of a built-in value, such as a method of a
+// string or list.
+func property(object any, name string) (any, bool) {
+	switch object := object.(type) {
+	case loxObject:
+		return object.get(name)
+	case string:
+		return stringMethod(object, name)
+	case int64, *big.Int, float64:
+		return numberMethod(object, name)
+	}
+	return nil, false
+}
+
+func (i *Interpreter) VisitInterpolationExpr(e *ast.InterpolationExpr) any {
+	var result strings.Builder
+	for _, part := range e.Parts {
+		value, err := i.evaluate(part)
+		if err != nil {
+			return err
+		}
+		result.WriteString(stringify(value))
+	}
+	return result.String()
+}
+
 func (i *Interpreter) VisitExpressionStmt(s *ast.ExpressionStmt) error {
-	i.evaluate(s.Expression)
-	return nil
+	_, err := i.evaluate(s.Expression)
+	return err
 }
 
 func (i *Interpreter) VisitPrintStmt(s *ast.PrintStmt) error {
-	expr := i.evaluate(s.Expression)
-	fmt.Printf("%v\n", expr)
+	value, err := i.evaluate(s.Expression)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(stringify(value))
 	return nil
 }
 
 func (i *Interpreter) VisitVarStmt(s *ast.VarStmt) error {
 	var value any = nil
 	if s.Value != nil {
-		value = i.evaluate(s.Value)
+		v, err := i.evaluate(s.Value)
+		if err != nil {
+			return err
+		}
+		value = v
 	}
 
+	nameFunction(s.Value, value, s.Name.Lexeme)
 	i.env.Define(s.Name.Lexeme, value)
 	return nil
 }
 
-func (i *Interpreter) Interpret(statements []ast.Stmt) {
+// callAt invokes function after checking its arity, recording a stack frame
+// for the call made at line.
+func (i *Interpreter) callAt(function LoxCallable, args []any, line int) (any, error) {
+	if arity := function.Arity(); arity >= 0 && len(args) != arity {
+		return nil, &nativeError{
+			message: fmt.Sprintf("Expected %d arguments but got %d.", arity, len(args)),
+		}
+	}
+
+	if len(i.frames) >= maxCallDepth {
+		return nil, &nativeError{message: "Stack overflow."}
+	}
+
+	i.frames = append(i.frames, callFrame{function: function.Name(), file: i.file, line: line})
+	defer func() { i.frames = i.frames[:len(i.frames)-1] }()
+
+	result, err := function.Call(i, args)
+	if err, ok := err.(*nativeError); ok && err.stack == nil {
+		err.stack = i.stackTrace(line)
+	}
+	return result, err
+}
+
+// call invokes function on behalf of a native, such as a callback passed to
+// a list method. The call is attributed to the native's own call site.
+func (i *Interpreter) call(function LoxCallable, args []any) (any, error) {
+	line := 0
+	if len(i.frames) > 0 {
+		line = i.frames[len(i.frames)-1].line
+	}
+	return i.callAt(function, args, line)
+}
+
+func (i *Interpreter) VisitBlockStmt(s *ast.BlockStmt) error {
+	return i.executeBlock(s.Statements, NewEnclosedEnvironment(i.env))
+}
+
+func (i *Interpreter) VisitForInStmt(s *ast.ForInStmt) error {
+	iterable, err := i.evaluate(s.Iterable)
+	if err != nil {
+		return err
+	}
+
+	next, err := i.iterate(iterable)
+	if err != nil {
+		return atToken(err, s.In)
+	}
+
+	for {
+		if err := i.ctx.Err(); err != nil {
+			return atToken(cancelled(err), s.In)
+		}
+
+		value, ok, err := next()
+		if err != nil {
+			return atToken(err, s.In)
+		}
+		if !ok {
+			return nil
+		}
+
+		env := NewEnclosedEnvironment(i.env)
+		env.Define(s.Name.Lexeme, value)
+		if err := i.executeBlock([]ast.Stmt{s.Body}, env); err != nil {
+			control, ok := err.(*loopControl)
+			if !ok {
+				return err
+			}
+			if control.keyword.Type == ast.BREAK {
+				return nil
+			}
+		}
+	}
+}
+
+func (i *Interpreter) VisitBreakStmt(s *ast.BreakStmt) error {
+	return &loopControl{keyword: s.Keyword}
+}
+
+func (i *Interpreter) VisitContinueStmt(s *ast.ContinueStmt) error {
+	return &loopControl{keyword: s.Keyword}
+}
+
+func (i *Interpreter) VisitReturnStmt(s *ast.ReturnStmt) error {
+	var value any
+	if s.Value != nil {
+		var err error
+		value, err = i.evaluate(s.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return &returnValue{keyword: s.Keyword, value: value}
+}
+
+func (i *Interpreter) VisitThrowStmt(s *ast.ThrowStmt) error {
+	value, err := i.evaluate(s.Value)
+	if err != nil {
+		return err
+	}
+
+	if exception, ok := value.(*LoxException); ok {
+		return &thrownError{exception: exception}
+	}
+
+	return &thrownError{exception: &LoxException{
+		message: stringify(value),
+		value:   value,
+		line:    s.Keyword.Line,
+		stack:   i.stackTrace(s.Keyword.Line),
+	}}
+}
+
+func (i *Interpreter) VisitTryStmt(s *ast.TryStmt) error {
+	err := i.executeBlock(s.Body, NewEnclosedEnvironment(i.env))
+
+	if err != nil && s.CatchName != nil {
+		if exception, ok := toException(err); ok {
+			env := NewEnclosedEnvironment(i.env)
+			env.Define(s.CatchName.Lexeme, exception)
+			err = i.executeBlock(s.CatchBody, env)
+		}
+	}
+
+	if s.FinallyBody != nil {
+		if finallyErr := i.executeBlock(s.FinallyBody, NewEnclosedEnvironment(i.env)); finallyErr != nil {
+			return finallyErr
+		}
+	}
+
+	return err
+}
+
+// Interpret executes statements in order, stopping at the first runtime
+// error or call to exit().
+func (i *Interpreter) Interpret(statements []ast.Stmt) error {
 	for _, stmt := range statements {
 		if err := i.execute(stmt); err != nil {
-			i.errors = append(i.errors, err)
+			return err
 		}
 	}
+	return nil
 }
 
 func (i *Interpreter) execute(s ast.Stmt) error {
-	return s.Accept(i)
+	return i.annotate(s.Accept(i))
+}
+
+// executeBlock runs statements in env, restoring the current environment
+// afterwards.
+func (i *Interpreter) executeBlock(statements []ast.Stmt, env *Environment) error {
+	previous := i.env
+	i.env = env
+	defer func() { i.env = previous }()
+
+	for _, stmt := range statements {
+		if err := i.execute(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluate unwraps errors returned by expression visitors. Lox values never
+// implement error, so any error result aborts evaluation.
+func (i *Interpreter) evaluate(e ast.Expr) (any, error) {
+	value := e.Accept(i)
+	if err, ok := value.(error); ok {
+		return nil, i.annotate(err)
+	}
+	return value, nil
 }
 
-func (i *Interpreter) evaluate(e ast.Expr) any {
-	return e.Accept(i)
+// annotate records the stack trace of a runtime error the first time it is
+// seen, before any frames have been unwound.
+func (i *Interpreter) annotate(err error) error {
+	if err, ok := err.(*RuntimeError); ok && err.stack == nil {
+		err.stack = i.stackTrace(err.token.Line)
+	}
+	return err
+}
+
+// stringify converts a Lox value to the text shown by print and string
+// interpolation.
+func stringify(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatFloat(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// stringifyNested stringifies a value inside a container, where seen holds
+// the containers already being printed so that cycles terminate.
+func stringifyNested(value any, seen map[any]bool) string {
+	switch v := value.(type) {
+	case *LoxList:
+		return v.format(seen)
+	case *LoxMap:
+		return v.format(seen)
+	}
+	return stringify(value)
 }
 
 func isTruthy(obj any) bool {
@@ -199,14 +866,24 @@ func isTruthy(obj any) bool {
 	}
 }
 
-func extractFloats(a, b any) (float64, float64, bool) {
-	aF, ok := a.(float64)
-	if !ok {
-		return 0, 0, false
+// atToken attaches token to errors raised by natives, which have no source
+// position of their own. Other errors are returned unchanged.
+func atToken(err error, token *ast.Token) error {
+	if err, ok := err.(*nativeError); ok {
+		return &RuntimeError{token: token, message: err.message, stack: err.stack, cause: err.cause}
 	}
-	bF, ok := b.(float64)
-	if !ok {
-		return 0, 0, false
+	return err
+}
+
+// cancelled reports that the interpreter's context ended while a script was
+// running.
+func cancelled(err error) *nativeError {
+	return &nativeError{message: fmt.Sprintf("Script cancelled: %v.", err), cause: err}
+}
+
+func operandsError(operator *ast.Token) *RuntimeError {
+	return &RuntimeError{
+		token:   operator,
+		message: "Operands must be numbers.",
 	}
-	return aF, bF, true
 }
@@ -0,0 +1,276 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+// run executes source in i, failing the test on scan or parse errors.
+func run(t *testing.T, i *Interpreter, source string) error {
+	t.Helper()
+
+	scanner := NewScanner()
+	tokens, ok := scanner.scanTokens(source)
+	if !ok {
+		t.Fatalf("scan %q: %v", source, scanner.errors)
+	}
+
+	parser := NewParser()
+	statements, ok := parser.parse(tokens)
+	if !ok {
+		t.Fatalf("parse %q: %v", source, parser.errors)
+	}
+
+	return i.Interpret(statements)
+}
+
+// eval returns the value of the Lox expression source, failing the test on
+// any error.
+func eval(t *testing.T, i *Interpreter, source string) any {
+	t.Helper()
+
+	if err := run(t, i, "var result = "+source+";"); err != nil {
+		t.Fatalf("eval %q: %v", source, err)
+	}
+	value, _ := i.env.Get("result")
+	return value
+}
+
+func TestOptionalChain(t *testing.T) {
+	tests := []struct {
+		source string
+		want   any
+	}{
+		{`nothing?.b`, nil},
+		{`nothing?.b.c`, nil},
+		{`nothing?.m()`, nil},
+		{`nothing?.b[0].c()`, nil},
+		{`nothing?.b ?? "default"`, "default"},
+		{`e?.message`, "boom"},
+		{`e?.frames.len()`, int64(1)},
+	}
+
+	i := NewInterpreter()
+	if err := run(t, i, `var nothing = nil; var e; try { throw "boom"; } catch (caught) { e = caught; }`); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := eval(t, i, test.source); got != test.want {
+			t.Errorf("%s = %v, want %v", test.source, got, test.want)
+		}
+	}
+
+	// Parentheses end the chain, so the access outside them is not skipped.
+	if err := run(t, i, `(nothing?.b).c;`); err == nil {
+		t.Error("(nothing?.b).c succeeded, want undefined property error")
+	}
+}
+
+func TestCompoundAssignment(t *testing.T) {
+	tests := []struct {
+		source string
+		want   any
+	}{
+		{`x += 2`, int64(3)},
+		{`x -= 2`, int64(-1)},
+		{`x *= 5`, int64(5)},
+		{`x /= 2`, 0.5},
+		{`x %= 1`, int64(0)},
+		{`x++`, int64(1)},
+		{`++x`, int64(2)},
+		{`x--`, int64(1)},
+		{`--x`, int64(0)},
+		{`s += "b"`, "ab"},
+		{`1 - -1`, int64(2)},
+	}
+
+	for _, test := range tests {
+		i := NewInterpreter()
+		if err := run(t, i, `var x = 1; var s = "a";`); err != nil {
+			t.Fatal(err)
+		}
+		if got := eval(t, i, test.source); got != test.want {
+			t.Errorf("%s = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
+
+// TestCompoundAssignmentEvaluatesTargetOnce checks that the object and
+// index of an indexed target are evaluated once, not once to read and
+// again to write.
+func TestCompoundAssignmentEvaluatesTargetOnce(t *testing.T) {
+	i := NewInterpreter()
+	err := run(t, i, `
+var calls = 0;
+var next = fun () { calls += 1; return 0; };
+var xs = [10];
+xs[next()] += 1;
+xs[next()]++;
+--xs[next()];
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eval(t, i, "calls"); got != int64(3) {
+		t.Errorf("index evaluated %v times for three updates, want 3", got)
+	}
+	if got := eval(t, i, "xs[0]"); got != int64(11) {
+		t.Errorf("xs[0] = %v, want 11", got)
+	}
+}
+
+func TestDecrementOperatorScansAsOneToken(t *testing.T) {
+	// "--" is the decrement operator, so 1--1 no longer parses as 1 - -1.
+	tokens, ok := NewScanner().scanTokens("1--1;")
+	if !ok {
+		t.Fatal("scan failed")
+	}
+	if _, ok := NewParser().parse(tokens); ok {
+		t.Error("1--1 parsed, want a syntax error")
+	}
+}
+
+func TestLoopControl(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`for (x in [1, 2, 3]) { log.push(x); { continue; } log.push("skipped"); }`, "[1, 2, 3]"},
+		{`for (x in [1, 2, 3]) { { { log.push(x); break; } } log.push("skipped"); }`, "[1]"},
+		{`for (x in [1, 2]) { for (y in [3, 4]) { log.push(y); break; } log.push(x); }`, "[3, 1, 3, 2]"},
+		{`for (x in [1, 2]) { try { continue; } finally { log.push(x); } log.push("skipped"); }`, "[1, 2]"},
+		{`for (x in [1, 2]) { try { try { break; } finally { log.push("inner"); } } finally { log.push("outer"); } }`, "[inner, outer]"},
+	}
+
+	for _, test := range tests {
+		i := NewInterpreter()
+		if err := run(t, i, "var log = [];\n"+test.source); err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+		if got := stringify(eval(t, i, "log")); got != test.want {
+			t.Errorf("%s: log = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+	for _, source := range []string{
+		`break;`,
+		`{ continue; }`,
+		`var f = fun () { break; };`,
+		`for (x in [1]) { var f = fun () { break; }; }`,
+		`for (x in [1]) { var f = fun () { continue; }; }`,
+	} {
+		tokens, ok := NewScanner().scanTokens(source)
+		if !ok {
+			t.Fatalf("scan %q failed", source)
+		}
+		parser := NewParser()
+		if _, ok := parser.parse(tokens); ok {
+			t.Errorf("%s parsed, want a syntax error", source)
+			continue
+		}
+		if got := parser.errors[0].Error(); !strings.Contains(got, "outside of a loop.") {
+			t.Errorf("%s: error = %q, want 'outside of a loop.'", source, got)
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"Hello ${name}, you are ${age + 1}"`, "Hello Ann, you are 4"},
+		{`"${name}"`, "Ann"},
+		{`"${1}${2}"`, "12"},
+		{`"nested ${"in ${name}"}"`, "nested in Ann"},
+		{`"${"a${1}b${2}c"}-${"d"}"`, "a1b2c-d"},
+		{`"${ "x" + "y" }"`, "xy"},
+		{`"${[1, 2]} ${nil} ${true} ${1.5} ${{"a": 1}}"`, "[1, 2] nil true 1.5 {a: 1}"},
+		{`"${ {"k": "v"}["k"] }"`, "v"},
+		{`"cost: $5 {not} \${name}"`, "cost: $5 {not} ${name}"},
+	}
+
+	i := NewInterpreter()
+	if err := run(t, i, `var name = "Ann"; var age = 3;`); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := eval(t, i, test.source); got != test.want {
+			t.Errorf("%s = %q, want %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestStringInterpolationErrors(t *testing.T) {
+	for _, source := range []string{
+		`"${1";`,
+		`"${}";`,
+		`"${1 + }";`,
+		`"${ x "y" }";`,
+		`"${ x "y${1}" }";`,
+		`"${ x "y" }z${1}";`,
+	} {
+		tokens, ok := NewScanner().scanTokens(source)
+		if ok {
+			if _, ok = NewParser().parse(tokens); ok {
+				t.Errorf("%s parsed, want a syntax error", source)
+			}
+		}
+	}
+}
+
+func TestStringifyCyclicList(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`a`, "[1, [...]]"},
+		{`"${a}"`, "[1, [...]]"},
+		{`[a, a]`, "[[1, [...]], [1, [...]]]"},
+		{`b`, "[[[...]]]"},
+		{`",".join(a)`, "1,[1, [...]]"},
+	}
+
+	i := NewInterpreter()
+	if err := run(t, i, `var a = [1]; a.push(a); var b = []; b.push([b]);`); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestStringifyCyclicMap(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`m`, "{self: {...}}"},
+		{`"${m}"`, "{self: {...}}"},
+		{`n`, "{list: [1, {...}]}"},
+		{`l`, "[{list: [...]}]"},
+	}
+
+	i := NewInterpreter()
+	err := run(t, i, `
+var m = {};
+m["self"] = m;
+var n = {"list": [1]};
+n["list"].push(n);
+var l = [];
+l.push({"list": l});
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
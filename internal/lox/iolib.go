@@ -0,0 +1,116 @@
+package lox
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+func (i *Interpreter) defineIONatives() {
+	i.globals.Define("readLine", NewNativeFunction("readLine", 0, ioReadLine))
+	i.globals.Define("readFile", NewNativeFunction("readFile", 1, ioReadFile))
+	i.globals.Define("writeFile", NewNativeFunction("writeFile", 2, ioWriteFile))
+	i.globals.Define("appendFile", NewNativeFunction("appendFile", 2, ioAppendFile))
+	i.globals.Define("listDir", NewNativeFunction("listDir", 1, ioListDir))
+	i.globals.Define("exists", NewNativeFunction("exists", 1, ioExists))
+}
+
+// SetStdin sets the reader used by readLine(). A *bufio.Reader is used
+// directly, so it can be shared with other readers of the same input.
+func (i *Interpreter) SetStdin(r io.Reader) {
+	i.stdin = bufio.NewReader(r)
+}
+
+// SetFileSystem sets the filesystem used by the file natives and by
+// import. A nil FileSystem disables file access.
+func (i *Interpreter) SetFileSystem(fs FileSystem) {
+	i.fs = fs
+}
+
+// ioReadLine returns the next line of input without its line terminator, or
+// nil at end of input.
+func ioReadLine(i *Interpreter, args []any) (any, error) {
+	line, err := i.stdin.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, &nativeError{message: err.Error()}
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func ioReadFile(i *Interpreter, args []any) (any, error) {
+	fs, path, err := i.fileArgs("readFile", args)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, &nativeError{message: err.Error()}
+	}
+	return string(data), nil
+}
+
+func ioWriteFile(i *Interpreter, args []any) (any, error) {
+	fs, path, err := i.fileArgs("writeFile", args)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.WriteFile(path, []byte(stringify(args[1]))); err != nil {
+		return nil, &nativeError{message: err.Error()}
+	}
+	return nil, nil
+}
+
+func ioAppendFile(i *Interpreter, args []any) (any, error) {
+	fs, path, err := i.fileArgs("appendFile", args)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.AppendFile(path, []byte(stringify(args[1]))); err != nil {
+		return nil, &nativeError{message: err.Error()}
+	}
+	return nil, nil
+}
+
+func ioListDir(i *Interpreter, args []any) (any, error) {
+	fs, path, err := i.fileArgs("listDir", args)
+	if err != nil {
+		return nil, err
+	}
+	names, err := fs.ReadDir(path)
+	if err != nil {
+		return nil, &nativeError{message: err.Error()}
+	}
+	elements := make([]any, len(names))
+	for idx, name := range names {
+		elements[idx] = name
+	}
+	return NewLoxList(elements), nil
+}
+
+func ioExists(i *Interpreter, args []any) (any, error) {
+	fs, path, err := i.fileArgs("exists", args)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := fs.Exists(path)
+	if err != nil {
+		return nil, &nativeError{message: err.Error()}
+	}
+	return ok, nil
+}
+
+// fileArgs checks that file access is enabled and that the first argument
+// to the native name is a path.
+func (i *Interpreter) fileArgs(name string, args []any) (FileSystem, string, error) {
+	if i.fs == nil {
+		return nil, "", &nativeError{message: "File access is disabled."}
+	}
+	path, ok := args[0].(string)
+	if !ok {
+		return nil, "", &nativeError{message: "Path passed to " + name + " must be a string."}
+	}
+	return i.fs, path, nil
+}
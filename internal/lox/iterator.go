@@ -0,0 +1,112 @@
+package lox
+
+import "fmt"
+
+// LoxRange is the lazy sequence of numbers produced by range(). The bounds
+// may be integers or floats; a range of integers yields integers.
+type LoxRange struct {
+	start, end, step any
+}
+
+func (r *LoxRange) String() string {
+	return fmt.Sprintf("range(%s, %s, %s)", stringify(r.start), stringify(r.end), stringify(r.step))
+}
+
+// iterate returns a function yielding successive values of a for-in loop
+// over value. The function reports false once the sequence is exhausted.
+//
+// Lists yield their elements, maps their keys, strings their characters and
+// ranges their numbers. Any other object can take part by providing an
+// iter() method that returns an iterator, whose next() method returns each
+// value in turn and nil when there are no more.
+func (i *Interpreter) iterate(value any) (func() (any, bool, error), error) {
+	switch value := value.(type) {
+	case *LoxList:
+		idx := 0
+		return func() (any, bool, error) {
+			if idx >= len(value.elements) {
+				return nil, false, nil
+			}
+			idx++
+			return value.elements[idx-1], true, nil
+		}, nil
+
+	case *LoxMap:
+		keys := make([]any, len(value.keys))
+		copy(keys, value.keys)
+		return sliceIterator(keys), nil
+
+	case string:
+		chars := []any{}
+		for _, r := range value {
+			chars = append(chars, string(r))
+		}
+		return sliceIterator(chars), nil
+
+	case *LoxRange:
+		n := value.start
+		ascending, _ := compareNumbers(value.step, int64(0))
+		return func() (any, bool, error) {
+			c, ok := compareNumbers(n, value.end)
+			if !ok || c*ascending >= 0 {
+				return nil, false, nil
+			}
+			current := n
+			n = add(n, value.step)
+			return current, true, nil
+		}, nil
+
+	case loxObject:
+		return i.protocolIterator(value)
+	}
+
+	return nil, &nativeError{message: "Can only iterate over lists, maps, strings, ranges and iterable objects."}
+}
+
+func (i *Interpreter) protocolIterator(object loxObject) (func() (any, bool, error), error) {
+	iter, ok := object.get("iter")
+	if !ok {
+		return nil, &nativeError{message: "Object is not iterable; it has no iter() method."}
+	}
+	iterFn, ok := iter.(LoxCallable)
+	if !ok {
+		return nil, &nativeError{message: "Object's iter property must be a method."}
+	}
+
+	iterator, err := i.call(iterFn, []any{})
+	if err != nil {
+		return nil, err
+	}
+
+	it, ok := iterator.(loxObject)
+	if !ok {
+		return nil, &nativeError{message: "iter() must return an object with a next() method."}
+	}
+	next, ok := it.get("next")
+	if !ok {
+		return nil, &nativeError{message: "iter() must return an object with a next() method."}
+	}
+	nextFn, ok := next.(LoxCallable)
+	if !ok {
+		return nil, &nativeError{message: "Iterator's next property must be a method."}
+	}
+
+	return func() (any, bool, error) {
+		value, err := i.call(nextFn, []any{})
+		if err != nil {
+			return nil, false, err
+		}
+		return value, value != nil, nil
+	}, nil
+}
+
+func sliceIterator(values []any) func() (any, bool, error) {
+	idx := 0
+	return func() (any, bool, error) {
+		if idx >= len(values) {
+			return nil, false, nil
+		}
+		idx++
+		return values[idx-1], true, nil
+	}
+}
@@ -0,0 +1,98 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestForInSequences(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`for (c in "héllo") log.push(c);`, "[h, é, l, l, o]"},
+		{`for (c in "") log.push(c);`, "[]"},
+		{`for (n in range(3)) log.push(n);`, "[0, 1, 2]"},
+		{`for (n in range(2, 5)) log.push(n);`, "[2, 3, 4]"},
+		{`for (n in range(3, 0)) log.push(n);`, "[]"},
+		{`for (n in range(5, 0, -2)) log.push(n);`, "[5, 3, 1]"},
+		{`for (n in range(0, 1, 0.25)) log.push(n);`, "[0, 0.25, 0.5, 0.75]"},
+		{`for (n in range(1, -1, -0.5)) log.push(n);`, "[1, 0.5, 0, -0.5]"},
+		{`for (k in {"a": 1, "b": 2}) log.push(k);`, "[a, b]"},
+	}
+
+	for _, test := range tests {
+		i := NewInterpreter()
+		if err := run(t, i, "var log = [];\n"+test.source); err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+		if got := stringify(eval(t, i, "log")); got != test.want {
+			t.Errorf("%s: log = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestForInMapSnapshotsKeys(t *testing.T) {
+	i := NewInterpreter()
+	err := run(t, i, `
+var log = [];
+var m = {"a": 1, "b": 2, "c": 3};
+for (k in m) {
+  log.push(k);
+  m.remove("b");
+  m["d"] = 4;
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stringify(eval(t, i, "log")); got != "[a, b, c]" {
+		t.Errorf("log = %s, want [a, b, c]", got)
+	}
+	if got := stringify(eval(t, i, "m")); got != "{a: 1, c: 3, d: 4}" {
+		t.Errorf("m = %s, want {a: 1, c: 3, d: 4}", got)
+	}
+}
+
+func TestForInIteratorProtocol(t *testing.T) {
+	i := NewInterpreter()
+	i.SetFileSystem(NewReadOnlyFileSystem(fstest.MapFS{
+		"countdown.lox": {Data: []byte(`
+import "cursor.lox" as cursor;
+var iter = fun () { return cursor; };
+`)},
+		"cursor.lox": {Data: []byte(`
+var n = 3;
+var next = fun () {
+  n -= 1;
+  return n >= 0 ? n : nil;
+};
+`)},
+		"plain.lox":  {Data: []byte(`var x = 1;`)},
+		"broken.lox": {Data: []byte(`var iter = fun () { return 1; };`)},
+	}))
+
+	if err := run(t, i, `var log = []; import "countdown.lox" as c; for (n in c) log.push(n);`); err != nil {
+		t.Fatal(err)
+	}
+	if got := stringify(eval(t, i, "log")); got != "[2, 1, 0]" {
+		t.Errorf("log = %s, want [2, 1, 0]", got)
+	}
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`for (x in 1) {}`, "Can only iterate over lists, maps, strings, ranges and iterable objects."},
+		{`import "plain.lox" as p; for (x in p) {}`, "Object is not iterable; it has no iter() method."},
+		{`import "broken.lox" as b; for (x in b) {}`, "iter() must return an object with a next() method."},
+	}
+	for _, test := range tests {
+		err := run(t, i, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package lox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+)
+
+func newJSONNamespace() *LoxNamespace {
+	ns := NewLoxNamespace("json")
+	ns.DefineNative("parse", 1, jsonParse)
+	ns.DefineNative("stringify", -1, jsonStringify)
+	return ns
+}
+
+// jsonParse decodes a JSON document into Lox values. Objects become maps
+// with their keys in document order.
+func jsonParse(i *Interpreter, args []any) (any, error) {
+	source, ok := args[0].(string)
+	if !ok {
+		return nil, &nativeError{message: "Argument to json.parse must be a string."}
+	}
+
+	dec := json.NewDecoder(strings.NewReader(source))
+	dec.UseNumber()
+	value, err := decodeJSONValue(dec)
+	offset := dec.InputOffset()
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			return value, nil
+		} else if err == nil {
+			err = errors.New("unexpected data after top-level value")
+			offset += int64(len(source[offset:]) - len(strings.TrimLeft(source[offset:], " \t\r\n")))
+		}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = syntaxErr.Offset
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = errors.New("unexpected end of input")
+		offset = int64(len(source))
+	}
+
+	line, column := jsonPosition(source, offset)
+	return nil, &nativeError{
+		message: fmt.Sprintf("Invalid JSON at line %d, column %d: %s.", line, column, err.Error()),
+	}
+}
+
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '[':
+			elements := []any{}
+			for dec.More() {
+				element, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return NewLoxList(elements), nil
+
+		case '{':
+			m := NewLoxMap()
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				m.Set(key.(string), value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return m, nil
+		}
+
+	case json.Number:
+		// Numbers without a fraction or exponent decode as integers.
+		n, ok := parseNumber(string(token))
+		if !ok {
+			return nil, errors.New("number out of range")
+		}
+		return n, nil
+	}
+
+	// Strings, numbers, booleans and null decode directly to Lox values.
+	return token, nil
+}
+
+// jsonPosition converts a byte offset into a 1-based line and column.
+func jsonPosition(source string, offset int64) (int, int) {
+	if offset > int64(len(source)) {
+		offset = int64(len(source))
+	}
+	before := source[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+	return line, column
+}
+
+// jsonStringify implements json.stringify(value) and
+// json.stringify(value, indent), where indent is a number of spaces or a
+// string to indent with.
+func jsonStringify(i *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, &nativeError{message: fmt.Sprintf("Expected 1 or 2 arguments but got %d.", len(args))}
+	}
+
+	indent := ""
+	if len(args) == 2 {
+		switch v := args[1].(type) {
+		case int64, *big.Int, float64:
+			n, ok := toInt(v)
+			if !ok || n < 0 || n > 10 {
+				return nil, &nativeError{message: "Indent must be an integer between 0 and 10."}
+			}
+			indent = strings.Repeat(" ", int(n))
+		case string:
+			indent = v
+		case nil:
+		default:
+			return nil, &nativeError{message: "Indent must be a number or a string."}
+		}
+	}
+
+	enc := &jsonEncoder{interpreter: i, indent: indent, seen: map[any]bool{}}
+	if err := enc.encode(args[0], 0); err != nil {
+		return nil, err
+	}
+	return enc.buf.String(), nil
+}
+
+// maxJSONDepth is the deepest nesting of lists and maps json.stringify
+// produces. It matches the limit encoding/json applies to json.parse.
+const maxJSONDepth = 10000
+
+type jsonEncoder struct {
+	interpreter *Interpreter
+	buf         bytes.Buffer
+	indent      string
+	// seen holds the lists and maps being encoded, to detect cycles.
+	seen map[any]bool
+}
+
+func (e *jsonEncoder) encode(value any, depth int) error {
+	switch v := value.(type) {
+	case nil:
+		e.buf.WriteString("null")
+	case bool, string, int64, *big.Int:
+		e.writeScalar(v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return &nativeError{message: "Cannot serialize " + stringify(v) + " to JSON."}
+		}
+		e.writeScalar(v)
+
+	case *LoxList:
+		if err := e.enter(v, depth); err != nil {
+			return err
+		}
+		defer delete(e.seen, v)
+
+		e.buf.WriteByte('[')
+		for idx, element := range v.elements {
+			if idx > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			if err := e.encode(element, depth+1); err != nil {
+				return err
+			}
+		}
+		if len(v.elements) > 0 {
+			e.newline(depth)
+		}
+		e.buf.WriteByte(']')
+
+	case *LoxMap:
+		if err := e.enter(v, depth); err != nil {
+			return err
+		}
+		defer delete(e.seen, v)
+
+		e.buf.WriteByte('{')
+		for idx, key := range v.keys {
+			if idx > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			e.writeScalar(stringify(key))
+			e.buf.WriteByte(':')
+			if e.indent != "" {
+				e.buf.WriteByte(' ')
+			}
+			if err := e.encode(v.lookup(key), depth+1); err != nil {
+				return err
+			}
+		}
+		if len(v.keys) > 0 {
+			e.newline(depth)
+		}
+		e.buf.WriteByte('}')
+
+	default:
+		return e.encodeWithHook(value, depth)
+	}
+	return nil
+}
+
+// encodeWithHook serializes objects that provide a toJSON() method by
+// encoding its result. Anything else cannot be represented in JSON.
+func (e *jsonEncoder) encodeWithHook(value any, depth int) error {
+	if object, ok := value.(loxObject); ok {
+		if hook, ok := object.get("toJSON"); ok {
+			if fn, ok := hook.(LoxCallable); ok {
+				converted, err := e.interpreter.call(fn, []any{})
+				if err != nil {
+					return err
+				}
+				return e.encode(converted, depth)
+			}
+		}
+	}
+	return &nativeError{message: "Cannot serialize " + stringify(value) + " to JSON."}
+}
+
+// enter marks container as being encoded at depth, rejecting cycles and
+// nesting deeper than json.parse accepts.
+func (e *jsonEncoder) enter(container any, depth int) error {
+	if e.seen[container] {
+		return &nativeError{message: "Cannot serialize a cyclic structure to JSON."}
+	}
+	if depth >= maxJSONDepth {
+		return &nativeError{message: fmt.Sprintf("Cannot serialize a structure nested more than %d levels deep to JSON.", maxJSONDepth)}
+	}
+	e.seen[container] = true
+	return nil
+}
+
+func (e *jsonEncoder) writeScalar(v any) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	e.buf.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+func (e *jsonEncoder) newline(depth int) {
+	if e.indent == "" {
+		return
+	}
+	e.buf.WriteByte('\n')
+	e.buf.WriteString(strings.Repeat(e.indent, depth))
+}
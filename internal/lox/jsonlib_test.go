@@ -0,0 +1,125 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONParseNumbers(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`json.parse("1")`, "1"},
+		{`json.parse("-0")`, "0"},
+		{`json.parse("1.5")`, "1.5"},
+		{`json.parse("2e3")`, "2000"},
+		{`json.parse("123456789012345678901234567890")`, "123456789012345678901234567890"},
+	}
+
+	i := NewInterpreter()
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+
+	err := run(t, i, `json.parse("[1, 1e400]");`)
+	if err == nil || !strings.Contains(err.Error(), "number out of range") {
+		t.Errorf(`json.parse("[1, 1e400]") error = %v, want number out of range`, err)
+	}
+}
+
+func TestJSONStringify(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`json.stringify({"a": [1, 2.5, "x", nil, true], "b": {}})`, `{"a":[1,2.5,"x",null,true],"b":{}}`},
+		{`json.stringify([1, {"k": "v"}], 2)`, "[\n  1,\n  {\n    \"k\": \"v\"\n  }\n]"},
+		{`json.stringify({"a": 1}, "\t")`, "{\n\t\"a\": 1\n}"},
+		{`json.stringify(2 ** 70)`, "1180591620717411303424"},
+		{`json.stringify("é\n\"")`, `"é\n\""`},
+		{`json.stringify({"z": 1, "a": 2})`, `{"z":1,"a":2}`},
+	}
+
+	i := NewInterpreter()
+	for _, test := range tests {
+		if got := eval(t, i, test.source); got != test.want {
+			t.Errorf("%s = %q, want %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []string{
+		`{"b":1,"a":[true,null,"é"]}`,
+		`[[],{},"",0,-1.5,123456789012345678901234567890]`,
+		`"\"\\\n"`,
+	}
+
+	i := NewInterpreter()
+	for _, source := range tests {
+		i.globals.Define("source", source)
+		if got := eval(t, i, "json.stringify(json.parse(source))"); got != source {
+			t.Errorf("round trip of %s = %s", source, got)
+		}
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`json.parse("{\"a\": }")`, "Invalid JSON at line 1, column 8"},
+		{`json.parse("[1,\n 2,,]")`, "Invalid JSON at line 2, column 5"},
+		{`json.parse("[1")`, "Invalid JSON at line 1, column 3"},
+		{`json.parse("1 2")`, "unexpected data after top-level value"},
+		{`json.parse(1)`, "Argument to json.parse must be a string."},
+		{`json.stringify(fun () {})`, "Cannot serialize <fn> to JSON."},
+		{`json.stringify(0 / 0)`, "Cannot serialize NaN to JSON."},
+		{`json.stringify(cycle)`, "Cannot serialize a cyclic structure to JSON."},
+		{`json.stringify(1, 11)`, "Indent must be an integer between 0 and 10."},
+	}
+
+	i := NewInterpreter()
+	if err := run(t, i, "var cycle = []; cycle.push(cycle);"); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		err := run(t, i, test.source+";")
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s error = %v, want %q", test.source, err, test.want)
+		}
+	}
+}
+
+func TestJSONStringifyDepth(t *testing.T) {
+	i := NewInterpreter()
+	err := run(t, i, `
+var deep = [];
+var inner = deep;
+for (n in range(9999)) {
+  var next = [];
+  inner.push(next);
+  inner = next;
+}
+var text = json.stringify(deep);
+var same = json.stringify(json.parse(text)) == text;
+inner.push({"a": []});
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eval(t, i, "same"); got != true {
+		t.Error("10000 levels of nesting did not round trip")
+	}
+
+	for _, source := range []string{`json.stringify(deep);`, `json.stringify({"k": deep});`} {
+		err := run(t, i, source)
+		if err == nil || !strings.Contains(err.Error(), "nested more than 10000 levels deep") {
+			t.Errorf("%s error = %v, want a nesting error", source, err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package lox
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+type LoxList struct {
+	elements []any
+}
+
+func NewLoxList(elements []any) *LoxList {
+	return &LoxList{elements: elements}
+}
+
+func (l *LoxList) String() string {
+	return l.format(map[any]bool{})
+}
+
+// format stringifies the list, printing [...] where it contains itself.
+// seen holds the containers already being printed.
+func (l *LoxList) format(seen map[any]bool) string {
+	if seen[l] {
+		return "[...]"
+	}
+	seen[l] = true
+	defer delete(seen, l)
+
+	parts := make([]string, len(l.elements))
+	for idx, element := range l.elements {
+		parts[idx] = stringifyNested(element, seen)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// Get returns the element at index, which must be an integer within bounds.
+func (l *LoxList) Get(index any) (any, error) {
+	idx, err := l.index(index)
+	if err != nil {
+		return nil, err
+	}
+	return l.elements[idx], nil
+}
+
+// Set replaces the element at index, which must be an integer within bounds.
+func (l *LoxList) Set(index any, value any) error {
+	idx, err := l.index(index)
+	if err != nil {
+		return err
+	}
+	l.elements[idx] = value
+	return nil
+}
+
+func (l *LoxList) index(index any) (int, error) {
+	n, ok := toInt(index)
+	if !ok {
+		if isInteger(index) {
+			return 0, &nativeError{message: "List index out of range."}
+		}
+		return 0, &nativeError{message: "List index must be an integer."}
+	}
+	if n < 0 || n >= int64(len(l.elements)) {
+		return 0, &nativeError{message: "List index out of range."}
+	}
+	return int(n), nil
+}
+
+func (l *LoxList) get(name string) (any, bool) {
+	switch name {
+	case "push":
+		return NewNativeFunction(name, 1, l.push), true
+	case "pop":
+		return NewNativeFunction(name, 0, l.pop), true
+	case "len":
+		return NewNativeFunction(name, 0, l.len), true
+	case "slice":
+		return NewNativeFunction(name, -1, l.slice), true
+	case "map":
+		return NewNativeFunction(name, 1, l.mapElements), true
+	case "filter":
+		return NewNativeFunction(name, 1, l.filter), true
+	case "sort":
+		return NewNativeFunction(name, -1, l.sort), true
+	}
+	return nil, false
+}
+
+func (l *LoxList) push(i *Interpreter, args []any) (any, error) {
+	l.elements = append(l.elements, args[0])
+	return nil, nil
+}
+
+func (l *LoxList) pop(i *Interpreter, args []any) (any, error) {
+	if len(l.elements) == 0 {
+		return nil, &nativeError{message: "Can't pop from an empty list."}
+	}
+	last := l.elements[len(l.elements)-1]
+	l.elements = l.elements[:len(l.elements)-1]
+	return last, nil
+}
+
+func (l *LoxList) len(i *Interpreter, args []any) (any, error) {
+	return int64(len(l.elements)), nil
+}
+
+// slice returns a new list with the elements from start up to, but not
+// including, end. end defaults to the length of the list.
+func (l *LoxList) slice(i *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, &nativeError{message: fmt.Sprintf("Expected 1 or 2 arguments but got %d.", len(args))}
+	}
+
+	bounds := []int{0, len(l.elements)}
+	for idx, arg := range args {
+		n, ok := toInt(arg)
+		if !ok && !isInteger(arg) {
+			return nil, &nativeError{message: "Slice bounds must be integers."}
+		}
+		if !ok || n < 0 || n > int64(len(l.elements)) {
+			return nil, &nativeError{message: "Slice bounds out of range."}
+		}
+		bounds[idx] = int(n)
+	}
+	if bounds[0] > bounds[1] {
+		return nil, &nativeError{message: "Slice start must not be greater than end."}
+	}
+
+	elements := make([]any, bounds[1]-bounds[0])
+	copy(elements, l.elements[bounds[0]:bounds[1]])
+	return NewLoxList(elements), nil
+}
+
+func (l *LoxList) mapElements(i *Interpreter, args []any) (any, error) {
+	fn, ok := args[0].(LoxCallable)
+	if !ok {
+		return nil, &nativeError{message: "Argument to map must be a function."}
+	}
+
+	elements := make([]any, 0, len(l.elements))
+	for _, element := range l.elements {
+		value, err := i.call(fn, []any{element})
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+	}
+	return NewLoxList(elements), nil
+}
+
+func (l *LoxList) filter(i *Interpreter, args []any) (any, error) {
+	fn, ok := args[0].(LoxCallable)
+	if !ok {
+		return nil, &nativeError{message: "Argument to filter must be a function."}
+	}
+
+	elements := []any{}
+	for _, element := range l.elements {
+		keep, err := i.call(fn, []any{element})
+		if err != nil {
+			return nil, err
+		}
+		if isTruthy(keep) {
+			elements = append(elements, element)
+		}
+	}
+	return NewLoxList(elements), nil
+}
+
+// sort sorts the list in place. Without arguments the list must hold only
+// numbers or only strings. Otherwise the argument is a comparator returning a
+// negative number when its first argument should come first.
+func (l *LoxList) sort(i *Interpreter, args []any) (any, error) {
+	if len(args) > 1 {
+		return nil, &nativeError{message: fmt.Sprintf("Expected 0 or 1 arguments but got %d.", len(args))}
+	}
+
+	if len(args) == 0 {
+		less, ok := naturalOrder(l.elements)
+		if !ok {
+			return nil, &nativeError{message: "Can only sort lists of numbers or strings without a comparator."}
+		}
+		sort.SliceStable(l.elements, less)
+		return nil, nil
+	}
+
+	fn, ok := args[0].(LoxCallable)
+	if !ok {
+		return nil, &nativeError{message: "Comparator must be a function."}
+	}
+
+	var sortErr error
+	sort.SliceStable(l.elements, func(a, b int) bool {
+		if sortErr != nil {
+			return false
+		}
+		result, err := i.call(fn, []any{l.elements[a], l.elements[b]})
+		if err != nil {
+			sortErr = err
+			return false
+		}
+		if !isNumber(result) {
+			sortErr = &nativeError{message: "Comparator must return a number."}
+			return false
+		}
+		c, _ := compareNumbers(result, int64(0))
+		return c < 0
+	})
+	return nil, sortErr
+}
+
+// naturalOrder returns a less function for elements if they are all numbers
+// or all strings.
+func naturalOrder(elements []any) (func(a, b int) bool, bool) {
+	if len(elements) == 0 {
+		return func(a, b int) bool { return false }, true
+	}
+
+	switch elements[0].(type) {
+	case int64, *big.Int, float64:
+		for _, element := range elements {
+			if !isNumber(element) {
+				return nil, false
+			}
+		}
+		return func(a, b int) bool {
+			c, _ := compareNumbers(elements[a], elements[b])
+			return c < 0
+		}, true
+	case string:
+		for _, element := range elements {
+			if _, ok := element.(string); !ok {
+				return nil, false
+			}
+		}
+		return func(a, b int) bool {
+			return elements[a].(string) < elements[b].(string)
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,85 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListMethods(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`l[0]`, "3"},
+		{`l[2.0]`, "2"},
+		{`l.len()`, "3"},
+		{`l.slice(1)`, "[1, 2]"},
+		{`l.slice(0, 2)`, "[3, 1]"},
+		{`l.slice(3)`, "[]"},
+		{`l.map((x) => x * 10)`, "[30, 10, 20]"},
+		{`l.filter((x) => x > 1)`, "[3, 2]"},
+		{`popped`, "9"},
+		{`numbers`, "[-1, 0.5, 2, 10]"},
+		{`words`, "[apple, banana, cherry]"},
+		{`descending`, "[3, 2, 1]"},
+		{`byLength`, "[bb, aa, c]"},
+	}
+
+	i := NewInterpreter()
+	err := run(t, i, `
+var l = [3, 1, 2];
+l.push(9);
+var popped = l.pop();
+var numbers = [10, 0.5, -1, 2];
+numbers.sort();
+var words = ["cherry", "apple", "banana"];
+words.sort();
+var descending = [1, 3, 2];
+descending.sort((a, b) => b - a);
+var byLength = ["c", "bb", "aa"];
+byLength.sort((a, b) => b.len() - a.len());
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestListMethodErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`l[3];`, "List index out of range."},
+		{`l[-1];`, "List index out of range."},
+		{`l[-1] = 0;`, "List index out of range."},
+		{`l[100000000000000000000];`, "List index out of range."},
+		{`l[0.5];`, "List index must be an integer."},
+		{`l["0"];`, "List index must be an integer."},
+		{`[].pop();`, "Can't pop from an empty list."},
+		{`l.slice(-1);`, "Slice bounds out of range."},
+		{`l.slice(0, 4);`, "Slice bounds out of range."},
+		{`l.slice(2, 1);`, "Slice start must not be greater than end."},
+		{`l.slice(0.5);`, "Slice bounds must be integers."},
+		{`[1, "a"].sort();`, "Can only sort lists of numbers or strings without a comparator."},
+		{`l.sort(1);`, "Comparator must be a function."},
+		{`l.sort((a, b) => "a");`, "Comparator must return a number."},
+		{`l.map(1);`, "Argument to map must be a function."},
+		{`l.filter(1);`, "Argument to filter must be a function."},
+	}
+
+	i := NewInterpreter()
+	if err := run(t, i, `var l = [1, 2, 3];`); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		err := run(t, i, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.source, err, test.want)
+		}
+	}
+}
@@ -1,77 +1,168 @@
 package lox
 
 import (
-	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/LucDeCaf/go-lox/internal/lox/error_reporters"
-	"os"
+	"io"
+	"io/fs"
 )
 
 type Lox struct {
-	interpreter *Interpreter
-	reporters   []error_reporters.ErrorReporter[error]
-	hadError    bool
+	interpreter     *Interpreter
+	reporters       []error_reporters.ErrorReporter[error]
+	hadError        bool
+	hadRuntimeError bool
 }
 
 func NewLox() *Lox {
 	return &Lox{
-		interpreter: NewInterpreter(),
-		reporters:   []error_reporters.ErrorReporter[error]{},
-		hadError:    false,
+		interpreter:     NewInterpreter(),
+		reporters:       []error_reporters.ErrorReporter[error]{},
+		hadError:        false,
+		hadRuntimeError: false,
 	}
 }
 
-func (l *Lox) RunPrompt() {
-	reader := bufio.NewReader(os.Stdin)
+// RunPrompt runs an interactive session until an empty line or the end of
+// input. It returns the code passed to exit() if a script calls it, and 0
+// otherwise.
+func (l *Lox) RunPrompt() int {
+	// The prompt reads through the interpreter's reader, shared with
+	// readLine(), so that neither swallows input buffered by the other.
+	reader := l.interpreter.stdin
 
 	for {
 		fmt.Print("> ")
-		line, _ := reader.ReadString('\n')
-		if line == "\n" {
-			break
+		line, err := reader.ReadString('\n')
+		if line == "\n" || (err != nil && line == "") {
+			return 0
+		}
+		if exit, ok := l.run(line, false); ok {
+			return exit.Code
 		}
-		l.run(line)
 		l.hadError = false
+		l.hadRuntimeError = false
 	}
 }
 
-func (l *Lox) RunFile(path string) (err error) {
-	source, err := os.ReadFile(path)
+// RunFile runs the script at path within fsys. Use HostFS to run scripts
+// from the host filesystem. Modules the script imports are read through the
+// FileSystem set with SetFileSystem, like any other file access.
+// args are exposed to the script through the args() native.
+//
+// RunFile returns the exit status for the script: the code passed to
+// exit(), 65 after a syntax error, 70 after a runtime error and 0
+// otherwise. The error is set only if the script cannot be read. The host
+// decides whether to end the process.
+func (l *Lox) RunFile(fsys fs.FS, path string, args []string) (int, error) {
+	source, err := fs.ReadFile(fsys, path)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	l.run(string(source))
+	l.interpreter.SetFile(path)
+	l.interpreter.SetArgs(args)
+	if exit, ok := l.run(string(source), true); ok {
+		return exit.Code, nil
+	}
 
 	if l.hadError {
-		os.Exit(65)
+		return 65, nil
+	}
+	if l.hadRuntimeError {
+		return 70, nil
 	}
 
-	return nil
+	return 0, nil
 }
 
-func (l *Lox) run(source string) {
+// run executes source, returning the ExitError if the script called exit().
+// isFile enables skipping a leading shebang line.
+func (l *Lox) run(source string, isFile bool) (*ExitError, bool) {
 	scanner := NewScanner()
+	scanner.allowShebang = isFile
 	tokens, scanOk := scanner.scanTokens(source)
 	for _, err := range scanner.errors {
-		for _, r := range l.reporters {
-			r.ReportError(err)
-		}
+		l.report(err)
 	}
 
 	parser := NewParser()
 	statements, parseOk := parser.parse(tokens)
 	for _, err := range parser.errors {
-		for _, r := range l.reporters {
-			r.ReportError(err)
-		}
+		l.report(err)
 	}
 
 	if !scanOk || !parseOk {
-		return
+		l.hadError = true
+		return nil, false
+	}
+
+	if err := l.interpreter.Interpret(statements); err != nil {
+		var exit *ExitError
+		if errors.As(err, &exit) {
+			return exit, true
+		}
+
+		l.report(err)
+		l.hadRuntimeError = true
 	}
 
-	l.interpreter.Interpret(statements)
+	return nil, false
+}
+
+// SetFileSystem restricts the filesystem scripts can access, both through
+// the file natives and through import. Pass nil to disable file access, or
+// a RootFileSystem to confine it to a directory.
+func (l *Lox) SetFileSystem(fs FileSystem) {
+	l.interpreter.SetFileSystem(fs)
+}
+
+// SetEnvLookup sets how env() reads environment variables. Pass nil to
+// stop scripts reading the host environment.
+func (l *Lox) SetEnvLookup(lookup func(name string) (string, bool)) {
+	l.interpreter.SetEnvLookup(lookup)
+}
+
+// SetStdin sets the input read by the prompt and by readLine().
+func (l *Lox) SetStdin(r io.Reader) {
+	l.interpreter.SetStdin(r)
+}
+
+// SetClock replaces the time source used by scripts, for example with a fake
+// clock in tests.
+func (l *Lox) SetClock(clock Clock) {
+	l.interpreter.SetClock(clock)
+}
+
+// SetContext sets a context that cancels running scripts when done.
+func (l *Lox) SetContext(ctx context.Context) {
+	l.interpreter.SetContext(ctx)
+}
+
+// SetStrictMath makes floating-point division by zero a runtime error
+// instead of yielding an infinity or NaN.
+func (l *Lox) SetStrictMath(strict bool) {
+	l.interpreter.SetStrictMath(strict)
+}
+
+// SetModulePath sets the directories searched for imported modules that are
+// not found relative to the importing file. The directories are resolved
+// within the FileSystem set with SetFileSystem.
+func (l *Lox) SetModulePath(dirs []string) {
+	l.interpreter.SetModulePath(dirs)
+}
+
+// SetRandomSeed seeds the random number generator used by scripts.
+func (l *Lox) SetRandomSeed(seed int64) {
+	l.interpreter.SetRandomSeed(seed)
+}
+
+func (l *Lox) report(err error) {
+	for _, r := range l.reporters {
+		r.ReportError(err)
+	}
 }
 
 func (l *Lox) RegisterErrorReporter(r error_reporters.ErrorReporter[error]) {
@@ -0,0 +1,97 @@
+package lox
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRunFileReturnsExitStatus(t *testing.T) {
+	tests := []struct {
+		source string
+		want   int
+	}{
+		{"print \"ok\";", 0},
+		{"exit(3);", 3},
+		{"exit(0);", 0},
+		{"var x = nil + 1;", 70},
+		{"var x = (;", 65},
+		{"try { exit(4); } catch (e) {}", 4},
+		{"var f = fun () { exit(5); }; f(); exit(6);", 5},
+		{"import \"lib.lox\" as lib; exit(7);", 2},
+		{"exit(args().len());", 2},
+		{"exit(int(args()[0]));", 3},
+		{"var f = fun () { return nil.x; }; f();", 70},
+		{"exit(0.5);", 70},
+		{"var s = \"unterminated;", 65},
+		{"print 1;\nvar x = (;\nexit(1);", 65},
+	}
+
+	for _, test := range tests {
+		fsys := fstest.MapFS{
+			"main.lox": {Data: []byte(test.source)},
+			"lib.lox":  {Data: []byte("exit(2);")},
+		}
+		l := NewLox()
+		l.SetFileSystem(NewReadOnlyFileSystem(fsys))
+		status, err := l.RunFile(fsys, "main.lox", []string{"3", "x"})
+		if err != nil {
+			t.Errorf("%s: %v", test.source, err)
+		}
+		if status != test.want {
+			t.Errorf("%s: status %d, want %d", test.source, status, test.want)
+		}
+	}
+
+	if _, err := NewLox().RunFile(fstest.MapFS{}, "missing.lox", nil); err == nil {
+		t.Error("RunFile of a missing script succeeded")
+	}
+}
+
+// errorLog records the errors reported by a Lox.
+type errorLog []error
+
+func (l *errorLog) ReportError(err error) {
+	*l = append(*l, err)
+}
+
+func TestRunFileSkipsShebang(t *testing.T) {
+	tests := []struct {
+		source string
+		status int
+		// line is the line of the first reported error, or 0 for none.
+		line int
+	}{
+		{"#!/usr/bin/env lox\nexit(3);", 3, 0},
+		{"#!/usr/bin/env lox", 0, 0},
+		{"#!/usr/bin/env lox\n\nvar x = nil + 1;", 70, 3},
+		{"#!/usr/bin/env lox\nvar x = (;", 65, 2},
+		{"exit(1);\n#!/usr/bin/env lox", 65, 2},
+	}
+
+	for _, test := range tests {
+		var errs errorLog
+		l := NewLox()
+		l.RegisterErrorReporter(&errs)
+		fsys := fstest.MapFS{"main.lox": {Data: []byte(test.source)}}
+		status, err := l.RunFile(fsys, "main.lox", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != test.status {
+			t.Errorf("%q: status %d, want %d", test.source, status, test.status)
+		}
+
+		line := 0
+		if len(errs) > 0 {
+			line = syntaxErrorLine(errs[0])
+			var runtimeErr *RuntimeError
+			if errors.As(errs[0], &runtimeErr) {
+				line = runtimeErr.token.Line
+			}
+		}
+		if line != test.line {
+			t.Errorf("%q: reported %v on line %d, want line %d", test.source, errs, line, test.line)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package lox
+
+import (
+	"math"
+	"math/big"
+	"strings"
+)
+
+// LoxMap is a hash map that remembers insertion order. Only nil, booleans,
+// numbers other than NaN and strings can be used as keys, since they are
+// immutable and compare by value. Numbers that compare equal, such as 1 and
+// 1.0, are the same key.
+type LoxMap struct {
+	keys []any
+	// values is indexed by hashKey of each key.
+	values map[any]any
+}
+
+func NewLoxMap() *LoxMap {
+	return &LoxMap{
+		keys:   []any{},
+		values: make(map[any]any),
+	}
+}
+
+func (m *LoxMap) String() string {
+	return m.format(map[any]bool{})
+}
+
+// format stringifies the map, printing {...} where it contains itself.
+// seen holds the containers already being printed.
+func (m *LoxMap) format(seen map[any]bool) string {
+	if seen[m] {
+		return "{...}"
+	}
+	seen[m] = true
+	defer delete(seen, m)
+
+	parts := make([]string, len(m.keys))
+	for idx, key := range m.keys {
+		parts[idx] = stringify(key) + ": " + stringifyNested(m.lookup(key), seen)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// Get returns the value stored under key, or nil if there is none.
+func (m *LoxMap) Get(key any) (any, error) {
+	hash, err := hashKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return m.values[hash], nil
+}
+
+func (m *LoxMap) Set(key any, value any) error {
+	hash, err := hashKey(key)
+	if err != nil {
+		return err
+	}
+	if _, ok := m.values[hash]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[hash] = value
+	return nil
+}
+
+func (m *LoxMap) Len() int {
+	return len(m.keys)
+}
+
+// lookup returns the value stored under a key already known to be in the
+// map.
+func (m *LoxMap) lookup(key any) any {
+	hash, _ := hashKey(key)
+	return m.values[hash]
+}
+
+// bigKey is the hash key of an integer too large for an int64.
+type bigKey string
+
+// hashKey returns the Go map key for a Lox map key, so that integral floats
+// share an entry with the equal integer.
+func hashKey(key any) (any, error) {
+	switch key := key.(type) {
+	case nil, bool, string, int64:
+		return key, nil
+	case *big.Int:
+		return bigKey(key.String()), nil
+	case float64:
+		if n, ok := truncate(key); ok && key == math.Trunc(key) {
+			return hashKey(n)
+		}
+		if !math.IsNaN(key) {
+			return key, nil
+		}
+	}
+	return nil, &nativeError{message: "Map keys must be nil, booleans, numbers or strings."}
+}
+
+func (m *LoxMap) get(name string) (any, bool) {
+	switch name {
+	case "has":
+		return NewNativeFunction(name, 1, m.has), true
+	case "remove":
+		return NewNativeFunction(name, 1, m.remove), true
+	case "keys":
+		return NewNativeFunction(name, 0, m.keyList), true
+	case "values":
+		return NewNativeFunction(name, 0, m.valueList), true
+	case "len":
+		return NewNativeFunction(name, 0, m.len), true
+	}
+	return nil, false
+}
+
+func (m *LoxMap) has(i *Interpreter, args []any) (any, error) {
+	hash, err := hashKey(args[0])
+	if err != nil {
+		return nil, err
+	}
+	_, ok := m.values[hash]
+	return ok, nil
+}
+
+// remove deletes key from the map, returning its value or nil if the key was
+// not present.
+func (m *LoxMap) remove(i *Interpreter, args []any) (any, error) {
+	hash, err := hashKey(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := m.values[hash]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(m.values, hash)
+	for idx, key := range m.keys {
+		if k, _ := hashKey(key); k == hash {
+			m.keys = append(m.keys[:idx], m.keys[idx+1:]...)
+			break
+		}
+	}
+	return value, nil
+}
+
+func (m *LoxMap) keyList(i *Interpreter, args []any) (any, error) {
+	keys := make([]any, len(m.keys))
+	copy(keys, m.keys)
+	return NewLoxList(keys), nil
+}
+
+func (m *LoxMap) valueList(i *Interpreter, args []any) (any, error) {
+	values := make([]any, len(m.keys))
+	for idx, key := range m.keys {
+		values[idx] = m.lookup(key)
+	}
+	return NewLoxList(values), nil
+}
+
+func (m *LoxMap) len(i *Interpreter, args []any) (any, error) {
+	return int64(len(m.keys)), nil
+}
@@ -0,0 +1,69 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapMethods(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`m`, "{a: 1, 1: one, true: yes, nil: none}"},
+		{`m["a"]`, "1"},
+		{`m["missing"]`, "nil"},
+		{`m[1.0]`, "one"},
+		{`m.has(1.0)`, "true"},
+		{`m.has("b")`, "false"},
+		{`m.keys()`, "[a, 1, true, nil]"},
+		{`m.values()`, "[1, one, yes, none]"},
+		{`m.len()`, "4"},
+		{`removed`, "2"},
+		{`m.remove("missing")`, "nil"},
+		{`f`, "{2: float, 0.5: half}"},
+		{`f[2]`, "float"},
+		{`{1: "a", 1.0: "b"}`, "{1: b}"},
+	}
+
+	i := NewInterpreter()
+	err := run(t, i, `
+var m = {"a": 1, "b": 2, 1: "one"};
+m[true] = "yes";
+m[nil] = "none";
+var removed = m.remove("b");
+var f = {};
+f[2.0] = "float";
+f[0.5] = "half";
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestMapKeyErrors(t *testing.T) {
+	const want = "Map keys must be nil, booleans, numbers or strings."
+	for _, source := range []string{
+		`m[0.0 / 0.0] = 1;`,
+		`m[0.0 / 0.0];`,
+		`m.has(0.0 / 0.0);`,
+		`m.remove(0.0 / 0.0);`,
+		`m[[]] = 1;`,
+		`m[{}];`,
+		`var x = {[1]: 1};`,
+	} {
+		i := NewInterpreter()
+		if err := run(t, i, `var m = {};`); err != nil {
+			t.Fatal(err)
+		}
+		err := run(t, i, source)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want %q", source, err, want)
+		}
+	}
+}
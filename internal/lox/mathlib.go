@@ -0,0 +1,194 @@
+package lox
+
+import (
+	"math"
+	"math/rand"
+)
+
+func newMathNamespace() *LoxNamespace {
+	ns := NewLoxNamespace("math")
+
+	ns.Define("pi", math.Pi)
+	ns.Define("e", math.E)
+	ns.Define("inf", math.Inf(1))
+	ns.Define("nan", math.NaN())
+
+	unary := map[string]func(float64) float64{
+		"sqrt":  math.Sqrt,
+		"sin":   math.Sin,
+		"cos":   math.Cos,
+		"tan":   math.Tan,
+		"asin":  math.Asin,
+		"acos":  math.Acos,
+		"atan":  math.Atan,
+		"exp":   math.Exp,
+		"log":   math.Log,
+		"log10": math.Log10,
+	}
+	for name, fn := range unary {
+		ns.DefineNative(name, 1, func(i *Interpreter, args []any) (any, error) {
+			n, err := numberArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			return fn(n[0]), nil
+		})
+	}
+
+	// floor, ceil and round return integers, leaving integer arguments
+	// unchanged.
+	rounding := map[string]func(float64) float64{
+		"floor": math.Floor,
+		"ceil":  math.Ceil,
+		"round": math.Round,
+	}
+	for name, fn := range rounding {
+		ns.DefineNative(name, 1, func(i *Interpreter, args []any) (any, error) {
+			if isInteger(args[0]) {
+				return args[0], nil
+			}
+			n, err := numberArgs(name, args)
+			if err != nil {
+				return nil, err
+			}
+			if rounded, ok := truncate(fn(n[0])); ok {
+				return rounded, nil
+			}
+			return n[0], nil
+		})
+	}
+
+	ns.DefineNative("abs", 1, mathAbs)
+	ns.DefineNative("pow", 2, func(i *Interpreter, args []any) (any, error) {
+		n, err := numberArgs("pow", args)
+		if err != nil {
+			return nil, err
+		}
+		return math.Pow(n[0], n[1]), nil
+	})
+	ns.DefineNative("atan2", 2, func(i *Interpreter, args []any) (any, error) {
+		n, err := numberArgs("atan2", args)
+		if err != nil {
+			return nil, err
+		}
+		return math.Atan2(n[0], n[1]), nil
+	})
+	ns.DefineNative("isNaN", 1, func(i *Interpreter, args []any) (any, error) {
+		n, err := numberArgs("isNaN", args)
+		if err != nil {
+			return nil, err
+		}
+		return math.IsNaN(n[0]), nil
+	})
+	ns.DefineNative("isFinite", 1, func(i *Interpreter, args []any) (any, error) {
+		n, err := numberArgs("isFinite", args)
+		if err != nil {
+			return nil, err
+		}
+		if isInteger(args[0]) {
+			return true, nil
+		}
+		return !math.IsInf(n[0], 0) && !math.IsNaN(n[0]), nil
+	})
+	ns.DefineNative("min", -1, mathMin)
+	ns.DefineNative("max", -1, mathMax)
+	ns.DefineNative("random", 0, mathRandom)
+	ns.DefineNative("randomInt", 2, mathRandomInt)
+	ns.DefineNative("seed", 1, mathSeed)
+
+	return ns
+}
+
+// numberArgs checks that every argument to the native name is a number.
+func numberArgs(name string, args []any) ([]float64, error) {
+	numbers := make([]float64, len(args))
+	for idx, arg := range args {
+		n, ok := toFloat(arg)
+		if !ok {
+			return nil, &nativeError{message: "Arguments to " + name + " must be numbers."}
+		}
+		numbers[idx] = n
+	}
+	return numbers, nil
+}
+
+func mathAbs(i *Interpreter, args []any) (any, error) {
+	if _, err := numberArgs("abs", args); err != nil {
+		return nil, err
+	}
+	if f, ok := args[0].(float64); ok {
+		return math.Abs(f), nil
+	}
+	if c, _ := compareNumbers(args[0], int64(0)); c < 0 {
+		n, _ := negate(args[0])
+		return n, nil
+	}
+	return args[0], nil
+}
+
+func mathMin(i *Interpreter, args []any) (any, error) {
+	return extreme("min", args, -1)
+}
+
+func mathMax(i *Interpreter, args []any) (any, error) {
+	return extreme("max", args, 1)
+}
+
+// extreme returns the smallest (sign -1) or largest (sign 1) argument,
+// keeping its type. The result is NaN if any argument is NaN.
+func extreme(name string, args []any, sign int) (any, error) {
+	if len(args) == 0 {
+		return nil, &nativeError{message: name + " expects at least one argument."}
+	}
+	n, err := numberArgs(name, args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := args[0]
+	for idx, arg := range args {
+		if math.IsNaN(n[idx]) {
+			return math.NaN(), nil
+		}
+		if c, _ := compareNumbers(arg, result); c == sign {
+			result = arg
+		}
+	}
+	return result, nil
+}
+
+// mathRandom returns a number in [0, 1).
+func mathRandom(i *Interpreter, args []any) (any, error) {
+	return i.rand.Float64(), nil
+}
+
+// mathRandomInt returns an integer in [lo, hi).
+func mathRandomInt(i *Interpreter, args []any) (any, error) {
+	lo, lok := toInt(args[0])
+	hi, hok := toInt(args[1])
+	if !lok || !hok {
+		return nil, &nativeError{message: "randomInt bounds must be integers."}
+	}
+	if hi <= lo {
+		return nil, &nativeError{message: "randomInt upper bound must be greater than lower bound."}
+	}
+	if hi-lo < 0 {
+		return nil, &nativeError{message: "randomInt range is too large."}
+	}
+	return lo + i.rand.Int63n(hi-lo), nil
+}
+
+func mathSeed(i *Interpreter, args []any) (any, error) {
+	seed, ok := toInt(args[0])
+	if !ok {
+		return nil, &nativeError{message: "Seed must be an integer."}
+	}
+	i.SetRandomSeed(seed)
+	return nil, nil
+}
+
+// SetRandomSeed reseeds the generator behind math.random, so that hosts can
+// make runs reproducible.
+func (i *Interpreter) SetRandomSeed(seed int64) {
+	i.rand = rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,100 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetRandomSeedIsReproducible(t *testing.T) {
+	const draws = `var result = [math.random(), math.random(), math.randomInt(0, 1000), math.randomInt(-5, 5)];`
+
+	sequence := func(seed int64) string {
+		i := NewInterpreter()
+		i.SetRandomSeed(seed)
+		if err := run(t, i, draws); err != nil {
+			t.Fatal(err)
+		}
+		return stringify(eval(t, i, "result"))
+	}
+
+	if a, b := sequence(42), sequence(42); a != b {
+		t.Errorf("seed 42 gave %s then %s, want the same draws", a, b)
+	}
+	if a, b := sequence(1), sequence(2); a == b {
+		t.Errorf("seeds 1 and 2 both gave %s", a)
+	}
+
+	i := NewInterpreter()
+	if err := run(t, i, "math.seed(42);\n"+draws); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stringify(eval(t, i, "result")), sequence(42); got != want {
+		t.Errorf("math.seed(42) gave %s, want %s", got, want)
+	}
+}
+
+func TestRandomRanges(t *testing.T) {
+	i := NewInterpreter()
+	i.SetRandomSeed(7)
+	for n := 0; n < 100; n++ {
+		f, ok := eval(t, i, "math.random()").(float64)
+		if !ok || f < 0 || f >= 1 {
+			t.Fatalf("math.random() = %v, want a float in [0, 1)", f)
+		}
+		r, ok := eval(t, i, "math.randomInt(-2, 3)").(int64)
+		if !ok || r < -2 || r >= 3 {
+			t.Fatalf("math.randomInt(-2, 3) = %v, want an integer in [-2, 3)", r)
+		}
+	}
+}
+
+func TestMathDomains(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`math.isNaN(math.sqrt(-1))`, "true"},
+		{`math.isNaN(math.log(-1))`, "true"},
+		{`math.isNaN(math.asin(2))`, "true"},
+		{`math.isNaN(math.acos(-2))`, "true"},
+		{`math.log(0) == -math.inf`, "true"},
+		{`math.isFinite(math.pow(0, -1))`, "false"},
+		{`math.isNaN(math.min(1, math.nan, 0))`, "true"},
+		{`math.isFinite(100000000000000000000000)`, "true"},
+		{`math.floor(-2.5)`, "-3"},
+		{`math.round(2.5)`, "3"},
+		{`math.ceil(7)`, "7"},
+		{`math.abs(-3)`, "3"},
+		{`math.max(1, 2.5, 2)`, "2.5"},
+	}
+
+	i := NewInterpreter()
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestMathArgumentErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`math.sqrt("4");`, "Arguments to sqrt must be numbers."},
+		{`math.pow(2, nil);`, "Arguments to pow must be numbers."},
+		{`math.floor("1.5");`, "Arguments to floor must be numbers."},
+		{`math.min();`, "min expects at least one argument."},
+		{`math.randomInt(0, 1.5);`, "randomInt bounds must be integers."},
+		{`math.randomInt(3, 3);`, "randomInt upper bound must be greater than lower bound."},
+		{`math.randomInt(-9223372036854775807, 9223372036854775807);`, "randomInt range is too large."},
+		{`math.seed(0.5);`, "Seed must be an integer."},
+	}
+
+	for _, test := range tests {
+		err := run(t, NewInterpreter(), test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LucDeCaf/go-lox/internal/lox/ast"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// moduleLoader tracks imported modules. Modules are read through the
+// interpreter's FileSystem, so hosts that restrict or disable file access
+// restrict imports in the same way. Module paths are slash-separated.
+type moduleLoader struct {
+	// searchPath lists directories tried after the importing file's own.
+	searchPath []string
+	// cache holds modules that finished executing, keyed by moduleKey.
+	cache map[string]*LoxNamespace
+	// loading is the chain of modules currently executing, used to detect
+	// circular imports.
+	loading []string
+}
+
+func newModuleLoader() *moduleLoader {
+	return &moduleLoader{
+		searchPath: []string{},
+		cache:      make(map[string]*LoxNamespace),
+		loading:    []string{},
+	}
+}
+
+// SetModulePath sets the directories searched for imported modules.
+func (i *Interpreter) SetModulePath(dirs []string) {
+	i.modules.searchPath = dirs
+}
+
+func (i *Interpreter) VisitImportStmt(s *ast.ImportStmt) error {
+	module, err := i.importModule(s.Keyword, s.Path.Literal.(string))
+	if err != nil {
+		return err
+	}
+	i.env.Define(s.Name.Lexeme, module)
+	return nil
+}
+
+// importModule returns the namespace of the module at path, executing it
+// the first time it is imported.
+func (i *Interpreter) importModule(keyword *ast.Token, path string) (*LoxNamespace, error) {
+	resolved, err := i.resolveModule(path)
+	if err != nil {
+		return nil, &RuntimeError{token: keyword, message: err.Error()}
+	}
+
+	key := i.moduleKey(resolved)
+	if module, ok := i.modules.cache[key]; ok {
+		return module, nil
+	}
+
+	for idx, loading := range i.modules.loading {
+		if loading == key {
+			cycle := append([]string{}, i.modules.loading[idx:]...)
+			cycle = append(cycle, key)
+			return nil, &RuntimeError{
+				token:   keyword,
+				message: "Circular import: " + strings.Join(cycle, " -> "),
+			}
+		}
+	}
+
+	source, err := i.fs.ReadFile(resolved)
+	if err != nil {
+		return nil, &RuntimeError{token: keyword, message: err.Error()}
+	}
+
+	scanner := NewScanner()
+	scanner.allowShebang = true
+	tokens, scanOk := scanner.scanTokens(string(source))
+	parser := NewParser()
+	statements, parseOk := parser.parse(tokens)
+	if !scanOk || !parseOk {
+		// The problems quote the module's source, which may be any readable
+		// file, so only their count and first line are reported.
+		problems := append(scanner.errors, parser.errors...)
+		line := syntaxErrorLine(problems[0])
+		for _, problem := range problems[1:] {
+			line = min(line, syntaxErrorLine(problem))
+		}
+		message := fmt.Sprintf("Could not load module '%s': syntax error on line %d", path, line)
+		if len(problems) > 1 {
+			message += fmt.Sprintf(" and %d more", len(problems)-1)
+		}
+		return nil, &RuntimeError{token: keyword, message: message + "."}
+	}
+
+	i.modules.loading = append(i.modules.loading, key)
+	defer func() { i.modules.loading = i.modules.loading[:len(i.modules.loading)-1] }()
+
+	i.frames = append(i.frames, callFrame{function: "<module>", file: i.file, line: keyword.Line})
+	previousFile := i.file
+	i.file = resolved
+	defer func() {
+		i.file = previousFile
+		i.frames = i.frames[:len(i.frames)-1]
+	}()
+
+	env := NewEnclosedEnvironment(i.globals)
+	if err := i.executeBlock(statements, env); err != nil {
+		return nil, err
+	}
+
+	module := &LoxNamespace{name: path, fields: env.values}
+	i.modules.cache[key] = module
+	return module, nil
+}
+
+// syntaxErrorLine returns the line a scan or parse error was reported on.
+func syntaxErrorLine(err error) int {
+	switch err := err.(type) {
+	case *ScanError:
+		return err.line
+	case *ParseError:
+		return err.token.Line
+	}
+	return 0
+}
+
+// resolveModule finds name relative to the importing file, then in each
+// directory of the search path. The script's file and the search path may
+// be host paths, so they are converted to slash-separated form first.
+func (i *Interpreter) resolveModule(name string) (string, error) {
+	if i.fs == nil {
+		return "", errors.New("File access is disabled.")
+	}
+
+	candidates := []string{path.Clean(name)}
+	if !path.IsAbs(name) {
+		base := "."
+		if i.file != "<stdin>" {
+			base = path.Dir(filepath.ToSlash(i.file))
+		}
+		candidates = []string{path.Join(base, name)}
+		for _, dir := range i.modules.searchPath {
+			candidates = append(candidates, path.Join(filepath.ToSlash(dir), name))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if ok, err := i.fs.Exists(candidate); err == nil && ok {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("Module '%s' not found.", name)
+}
+
+// moduleKey identifies the file at a resolved path, so that a module
+// imported under different spellings of its path is executed once. Host
+// files are keyed by their absolute path.
+func (i *Interpreter) moduleKey(resolved string) string {
+	if _, ok := i.fs.(OSFileSystem); ok {
+		if abs, err := filepath.Abs(filepath.FromSlash(resolved)); err == nil {
+			return filepath.ToSlash(abs)
+		}
+	}
+	return path.Clean(resolved)
+}
@@ -0,0 +1,232 @@
+package lox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// newModuleTestInterpreter returns an interpreter with a global list,
+// loads, that test modules push to when they execute.
+func newModuleTestInterpreter(t *testing.T) (*Interpreter, *LoxList) {
+	t.Helper()
+
+	i := NewInterpreter()
+	loads := NewLoxList([]any{})
+	i.globals.Define("loads", loads)
+	return i, loads
+}
+
+func TestImportExecutesEachModuleOnce(t *testing.T) {
+	i, loads := newModuleTestInterpreter(t)
+	i.SetFileSystem(NewReadOnlyFileSystem(fstest.MapFS{
+		"app/lib/x.lox": {Data: []byte(`loads.push("x"); var name = "x";`)},
+		"app/lib/y.lox": {Data: []byte(`import "x.lox" as x; var name = x.name;`)},
+	}))
+	i.SetFile("app/main.lox")
+
+	err := run(t, i, `
+import "./lib/x.lox" as a;
+import "lib/x.lox" as b;
+import "lib/../lib/x.lox" as c;
+import "lib/y.lox" as y;
+var result = a.name + b.name + c.name + y.name;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eval(t, i, "result"); got != "xxxx" {
+		t.Errorf("result = %v, want xxxx", got)
+	}
+	if got := len(loads.elements); got != 1 {
+		t.Errorf("x.lox executed %d times, want 1", got)
+	}
+}
+
+func TestImportHostPaths(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lib", "x.lox"), []byte(`loads.push("x");`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	i, loads := newModuleTestInterpreter(t)
+	i.SetFile(filepath.Join(dir, "main.lox"))
+
+	absolute := filepath.ToSlash(filepath.Join(dir, "lib", "x.lox"))
+	err := run(t, i, `
+import "lib/x.lox" as a;
+import "./lib/x.lox" as b;
+import "`+absolute+`" as c;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(loads.elements); got != 1 {
+		t.Errorf("x.lox executed %d times, want 1", got)
+	}
+}
+
+func TestImportSyntaxErrorDoesNotQuoteSource(t *testing.T) {
+	i, _ := newModuleTestInterpreter(t)
+	i.SetFileSystem(NewReadOnlyFileSystem(fstest.MapFS{
+		"secret.txt": {Data: []byte("user hunter2\npassword \"swordfish\n")},
+	}))
+
+	err := run(t, i, `var message; try { import "secret.txt" as s; } catch (e) { message = e.message; }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := eval(t, i, "message")
+	want := "Could not load module 'secret.txt': syntax error on line 1 and 1 more."
+	if got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+func TestImportIsConfinedToTheFileSystem(t *testing.T) {
+	dir := t.TempDir()
+	sandbox := filepath.Join(dir, "sandbox")
+	secret := filepath.Join(dir, "secret.lox")
+	if err := os.Mkdir(sandbox, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(secret, []byte(`loads.push("secret");`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sandbox, "ok.lox"), []byte(`loads.push("ok");`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(sandbox, "link.lox")); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := NewRootFileSystem(sandbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		fs   FileSystem
+		path string
+		want string
+	}{
+		{"parent", root, "../secret.lox", "Module '../secret.lox' not found."},
+		{"absolute", root, filepath.ToSlash(secret), "not found."},
+		{"symlink", root, "link.lox", "not found."},
+		{"embedded", NewReadOnlyFileSystem(os.DirFS(sandbox)), "../secret.lox", "not found."},
+		{"disabled", nil, "ok.lox", "File access is disabled."},
+	}
+
+	for _, test := range tests {
+		i, loads := newModuleTestInterpreter(t)
+		i.SetFileSystem(test.fs)
+		i.SetFile("main.lox")
+
+		err := run(t, i, fmt.Sprintf("import %q as m;", test.path))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: import %s error = %v, want %q", test.name, test.path, err, test.want)
+		}
+		if len(loads.elements) != 0 {
+			t.Errorf("%s: import %s ran %v", test.name, test.path, loads)
+		}
+	}
+
+	i, loads := newModuleTestInterpreter(t)
+	i.SetFileSystem(root)
+	i.SetFile("main.lox")
+	if err := run(t, i, `import "ok.lox" as m;`); err != nil || len(loads.elements) != 1 {
+		t.Errorf("import inside the root: error %v, loads %v", err, loads)
+	}
+}
+
+func TestImportCircularReportsChain(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`import "lib/a.lox" as a;`, "Circular import: lib/a.lox -> lib/b.lox -> lib/c.lox -> lib/a.lox"},
+		{`import "lib/self.lox" as s;`, "Circular import: lib/self.lox -> lib/self.lox"},
+	}
+
+	for _, test := range tests {
+		i, loads := newModuleTestInterpreter(t)
+		i.SetFileSystem(NewReadOnlyFileSystem(fstest.MapFS{
+			"lib/a.lox":    {Data: []byte(`import "b.lox" as b;`)},
+			"lib/b.lox":    {Data: []byte(`import "./c.lox" as c;`)},
+			"lib/c.lox":    {Data: []byte(`import "../lib/a.lox" as a; loads.push("c");`)},
+			"lib/self.lox": {Data: []byte(`import "self.lox" as s; loads.push("self");`)},
+		}))
+		i.SetFile("main.lox")
+
+		err := run(t, i, test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.source, err, test.want)
+		}
+		if len(loads.elements) != 0 {
+			t.Errorf("%s: ran %v past the circular import", test.source, loads)
+		}
+	}
+}
+
+func TestImportCacheReturnsSameNamespace(t *testing.T) {
+	i, _ := newModuleTestInterpreter(t)
+	i.SetFileSystem(NewReadOnlyFileSystem(fstest.MapFS{
+		"lib/x.lox": {Data: []byte(`var items = [];`)},
+		"lib/y.lox": {Data: []byte(`import "x.lox" as x; x.items.push("y");`)},
+	}))
+	i.SetFile("main.lox")
+
+	err := run(t, i, `
+import "lib/x.lox" as a;
+import "./lib/x.lox" as b;
+import "lib/y.lox" as y;
+var same = a == b;
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := i.env.Get("a")
+	b, _ := i.env.Get("b")
+	if a != b {
+		t.Errorf("imports of the same module returned %p and %p, want the same namespace", a, b)
+	}
+	if got := eval(t, i, "same"); got != true {
+		t.Errorf("a == b = %v, want true", got)
+	}
+	if got := stringify(eval(t, i, "a.items")); got != "[y]" {
+		t.Errorf("a.items = %s, want [y]", got)
+	}
+}
+
+func TestRuntimeErrorNamesTheFile(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"var x = nil + 1;", "[main.lox:1] RuntimeError: "},
+		{"\nimport \"lib/bad.lox\" as bad;", "[lib/bad.lox:2] RuntimeError: "},
+		{"import \"lib/fn.lox\" as fn;\n\nfn.fail();", "[lib/fn.lox:1] RuntimeError: "},
+		{"import \"lib/missing.lox\" as m;", "[main.lox:1] RuntimeError: Module 'lib/missing.lox' not found."},
+	}
+
+	for _, test := range tests {
+		i, _ := newModuleTestInterpreter(t)
+		i.SetFileSystem(NewReadOnlyFileSystem(fstest.MapFS{
+			"lib/bad.lox": {Data: []byte("var ok = 1;\nvar x = nil + 1;")},
+			"lib/fn.lox":  {Data: []byte("var fail = fun () { return nil + 1; };")},
+		}))
+		i.SetFile("main.lox")
+
+		err := run(t, i, test.source)
+		if err == nil || !strings.HasPrefix(err.Error(), test.want) {
+			t.Errorf("%q: error = %v, want it to start with %q", test.source, err, test.want)
+		}
+	}
+}
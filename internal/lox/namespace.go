@@ -0,0 +1,34 @@
+package lox
+
+// LoxNamespace is a read-only collection of named values, such as the
+// natives grouped under math.
+type LoxNamespace struct {
+	name   string
+	fields map[string]any
+}
+
+func NewLoxNamespace(name string) *LoxNamespace {
+	return &LoxNamespace{
+		name:   name,
+		fields: make(map[string]any),
+	}
+}
+
+// Define adds a value to the namespace.
+func (n *LoxNamespace) Define(name string, value any) {
+	n.fields[name] = value
+}
+
+// DefineNative adds a native function to the namespace.
+func (n *LoxNamespace) DefineNative(name string, arity int, fn func(*Interpreter, []any) (any, error)) {
+	n.fields[name] = NewNativeFunction(n.name+"."+name, arity, fn)
+}
+
+func (n *LoxNamespace) String() string {
+	return "<namespace " + n.name + ">"
+}
+
+func (n *LoxNamespace) get(name string) (any, bool) {
+	value, ok := n.fields[name]
+	return value, ok
+}
@@ -0,0 +1,87 @@
+package lox
+
+import "fmt"
+
+func (i *Interpreter) defineNatives() {
+	i.globals.Define("args", NewNativeFunction("args", 0, nativeArgs))
+	i.globals.Define("env", NewNativeFunction("env", 1, nativeEnv))
+	i.globals.Define("exit", NewNativeFunction("exit", 1, nativeExit))
+	i.globals.Define("range", NewNativeFunction("range", -1, nativeRange))
+	i.globals.Define("int", NewNativeFunction("int", 1, nativeInt))
+	i.globals.Define("float", NewNativeFunction("float", 1, nativeFloat))
+	i.globals.Define("math", newMathNamespace())
+	i.globals.Define("json", newJSONNamespace())
+	i.globals.Define("fromCharCode", NewNativeFunction("fromCharCode", 1, nativeFromCharCode))
+	i.globals.Define("regex", NewNativeFunction("regex", 1, nativeRegex))
+	i.defineIONatives()
+	i.defineTimeNatives()
+}
+
+func nativeArgs(i *Interpreter, args []any) (any, error) {
+	elements := make([]any, len(i.args))
+	for idx, arg := range i.args {
+		elements[idx] = arg
+	}
+	return NewLoxList(elements), nil
+}
+
+// SetEnvLookup sets the function env() reads environment variables through,
+// such as one exposing only an allowlist. It defaults to os.LookupEnv; nil
+// disables env(), so sandboxed scripts cannot read secrets from the host
+// environment.
+func (i *Interpreter) SetEnvLookup(lookup func(name string) (string, bool)) {
+	i.lookupEnv = lookup
+}
+
+func nativeEnv(i *Interpreter, args []any) (any, error) {
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, &nativeError{message: "Environment variable name must be a string."}
+	}
+
+	if i.lookupEnv == nil {
+		return nil, &nativeError{message: "Environment access is disabled."}
+	}
+	value, ok := i.lookupEnv(name)
+	if !ok {
+		return nil, nil
+	}
+	return value, nil
+}
+
+func nativeExit(i *Interpreter, args []any) (any, error) {
+	code, ok := toInt(args[0])
+	if !ok {
+		return nil, &nativeError{message: "Exit code must be an integer."}
+	}
+	return nil, &ExitError{Code: int(code)}
+}
+
+// nativeRange implements range(end), range(start, end) and
+// range(start, end, step).
+func nativeRange(i *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, &nativeError{message: fmt.Sprintf("Expected 1 to 3 arguments but got %d.", len(args))}
+	}
+
+	for _, arg := range args {
+		if !isNumber(arg) {
+			return nil, &nativeError{message: "Range bounds must be numbers."}
+		}
+	}
+
+	r := &LoxRange{start: int64(0), step: int64(1)}
+	switch len(args) {
+	case 1:
+		r.end = args[0]
+	case 2:
+		r.start, r.end = args[0], args[1]
+	case 3:
+		r.start, r.end, r.step = args[0], args[1], args[2]
+	}
+
+	if isEqual(r.step, int64(0)) {
+		return nil, &nativeError{message: "Range step must not be zero."}
+	}
+	return r, nil
+}
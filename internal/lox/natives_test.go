@@ -0,0 +1,70 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	i := NewInterpreter()
+	if got := stringify(eval(t, i, "args()")); got != "[]" {
+		t.Errorf("args() = %s, want []", got)
+	}
+
+	i.SetArgs([]string{"a", "b c"})
+	if err := run(t, i, `var result = args(); result.push("d");`); err != nil {
+		t.Fatal(err)
+	}
+	if got := stringify(eval(t, i, "args()")); got != "[a, b c]" {
+		t.Errorf("args() = %s, want [a, b c], unchanged by the script", got)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("LOX_TEST_ENV", "value")
+
+	i := NewInterpreter()
+	if got := eval(t, i, `env("LOX_TEST_ENV")`); got != "value" {
+		t.Errorf(`env("LOX_TEST_ENV") = %v, want value`, got)
+	}
+	if got := eval(t, i, `env("LOX_TEST_UNSET")`); got != nil {
+		t.Errorf(`env("LOX_TEST_UNSET") = %v, want nil`, got)
+	}
+
+	i.SetEnvLookup(func(name string) (string, bool) {
+		if name == "PUBLIC" {
+			return "visible", true
+		}
+		return "", false
+	})
+	if got := eval(t, i, `env("PUBLIC")`); got != "visible" {
+		t.Errorf(`env("PUBLIC") = %v, want visible`, got)
+	}
+	if got := eval(t, i, `env("LOX_TEST_ENV")`); got != nil {
+		t.Errorf(`env("LOX_TEST_ENV") = %v, want nil outside the allowlist`, got)
+	}
+
+	i.SetEnvLookup(nil)
+	err := run(t, i, `var x = env("LOX_TEST_ENV");`)
+	if err == nil || !strings.Contains(err.Error(), "Environment access is disabled.") {
+		t.Errorf("env() with access disabled: error = %v", err)
+	}
+}
+
+func TestExit(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`exit(1.5);`, "Exit code must be an integer."},
+		{`exit("1");`, "Exit code must be an integer."},
+		{`env(1);`, "Environment variable name must be a string."},
+	}
+
+	for _, test := range tests {
+		err := run(t, NewInterpreter(), test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.source, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,461 @@
+package lox
+
+import (
+	"github.com/LucDeCaf/go-lox/internal/lox/ast"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Lox numbers are either integers or floats. Integers are int64 values,
+// promoted to *big.Int when a result overflows and demoted again whenever
+// it fits, so scripts never see the difference. Floats are float64.
+//
+// Arithmetic on two integers yields an integer, except for /, which always
+// yields a float. Mixing an integer with a float yields a float.
+
+// maxIntegerBits bounds the size of the integers built by the left shift
+// and exponent operators, which are the operators that can grow a result
+// far beyond their operands. Each result is checked, so chaining them as
+// in (x ** 1000000) ** 1000000 fails rather than exhausting memory.
+const maxIntegerBits = 1 << 20
+
+func isNumber(value any) bool {
+	switch value.(type) {
+	case int64, *big.Int, float64:
+		return true
+	}
+	return false
+}
+
+func isInteger(value any) bool {
+	switch value.(type) {
+	case int64, *big.Int:
+		return true
+	}
+	return false
+}
+
+// toFloat converts any number to a float64, rounding integers too large to
+// be represented exactly.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f, true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// toInt converts integers and integral floats that fit in an int64, for use
+// as indexes, counts and other arguments of natives.
+func toInt(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case *big.Int:
+		if v.IsInt64() {
+			return v.Int64(), true
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// toBig converts integers and integral floats to a *big.Int. The result
+// must not be modified, since it may be shared with a Lox value.
+func toBig(value any) (*big.Int, bool) {
+	switch v := value.(type) {
+	case int64:
+		return big.NewInt(v), true
+	case *big.Int:
+		return v, true
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) || v != math.Trunc(v) {
+			return nil, false
+		}
+		n, _ := big.NewFloat(v).Int(nil)
+		return n, true
+	}
+	return nil, false
+}
+
+// normalize demotes n to an int64 when it fits.
+func normalize(n *big.Int) any {
+	if n.IsInt64() {
+		return n.Int64()
+	}
+	return n
+}
+
+// truncate converts a float to the integer with the same whole part.
+func truncate(f float64) (any, bool) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, false
+	}
+	f = math.Trunc(f)
+	if f >= math.MinInt64 && f < math.MaxInt64 {
+		return int64(f), true
+	}
+	n, _ := big.NewFloat(f).Int(nil)
+	return n, true
+}
+
+// compareNumbers orders two numbers exactly, even across integer and float.
+// ok is false if either value is not a number or is NaN.
+func compareNumbers(a, b any) (result int, ok bool) {
+	if x, isInt := a.(int64); isInt {
+		if y, isInt := b.(int64); isInt {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	x, ok := bigFloat(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := bigFloat(b)
+	if !ok {
+		return 0, false
+	}
+	return x.Cmp(y), true
+}
+
+func bigFloat(value any) (*big.Float, bool) {
+	switch v := value.(type) {
+	case int64:
+		return new(big.Float).SetInt64(v), true
+	case *big.Int:
+		return new(big.Float).SetInt(v), true
+	case float64:
+		if math.IsNaN(v) {
+			return nil, false
+		}
+		return big.NewFloat(v), true
+	}
+	return nil, false
+}
+
+// isEqual implements == for Lox values. Numbers are equal when they have
+// the same value regardless of type, so 1 == 1.0. As in IEEE 754, NaN is not
+// equal to anything, including itself; use math.isNaN to test for it.
+func isEqual(a, b any) bool {
+	if isNumber(a) && isNumber(b) {
+		c, ok := compareNumbers(a, b)
+		return ok && c == 0
+	}
+	return a == b
+}
+
+// arithmetic applies one of + - * / ~/ % ** to two numbers.
+func arithmetic(operator *ast.Token, left, right any) any {
+	if !isNumber(left) || !isNumber(right) {
+		return operandsError(operator)
+	}
+	if isInteger(left) && isInteger(right) && operator.Type != ast.SLASH {
+		return integerArithmetic(operator, left, right)
+	}
+
+	l, _ := toFloat(left)
+	r, _ := toFloat(right)
+	switch operator.Type {
+	case ast.PLUS:
+		return l + r
+	case ast.MINUS:
+		return l - r
+	case ast.STAR:
+		return l * r
+	case ast.SLASH:
+		return l / r
+	case ast.TILDE_SLASH:
+		return math.Trunc(l / r)
+	case ast.PERCENT:
+		return math.Mod(l, r)
+	case ast.STAR_STAR:
+		return math.Pow(l, r)
+	}
+
+	// Unreachable
+	return nil
+}
+
+func integerArithmetic(operator *ast.Token, left, right any) any {
+	if l, ok := left.(int64); ok {
+		if r, ok := right.(int64); ok {
+			if result, ok := smallArithmetic(operator.Type, l, r); ok {
+				return result
+			}
+		}
+	}
+
+	l, _ := toBig(left)
+	r, _ := toBig(right)
+	result := new(big.Int)
+	switch operator.Type {
+	case ast.PLUS:
+		result.Add(l, r)
+	case ast.MINUS:
+		result.Sub(l, r)
+	case ast.STAR:
+		result.Mul(l, r)
+	case ast.TILDE_SLASH, ast.PERCENT:
+		if r.Sign() == 0 {
+			return &RuntimeError{token: operator, message: "Division by zero."}
+		}
+		if operator.Type == ast.TILDE_SLASH {
+			result.Quo(l, r)
+		} else {
+			result.Rem(l, r)
+		}
+	case ast.STAR_STAR:
+		if r.Sign() < 0 {
+			lf, _ := toFloat(left)
+			rf, _ := toFloat(right)
+			return math.Pow(lf, rf)
+		}
+		// |l| ** r needs at most l.BitLen() * r bits.
+		if l.CmpAbs(big.NewInt(1)) > 0 && (!r.IsInt64() || r.Int64() > maxIntegerBits/int64(l.BitLen())) {
+			return &RuntimeError{token: operator, message: "Exponent is too large."}
+		}
+		result.Exp(l, r, nil)
+	}
+	return normalize(result)
+}
+
+// smallArithmetic computes an integer operation on int64 operands, with ok
+// false if the result does not fit in an int64 or needs special handling.
+func smallArithmetic(operator ast.TokenType, l, r int64) (result int64, ok bool) {
+	switch operator {
+	case ast.PLUS:
+		result = l + r
+		return result, (result > l) == (r > 0)
+	case ast.MINUS:
+		result = l - r
+		return result, (result < l) == (r > 0)
+	case ast.STAR:
+		if l == 0 || r == 0 {
+			return 0, true
+		}
+		result = l * r
+		return result, result/r == l && !(l == -1 && r == math.MinInt64) && !(r == -1 && l == math.MinInt64)
+	case ast.TILDE_SLASH:
+		if r == 0 || (l == math.MinInt64 && r == -1) {
+			return 0, false
+		}
+		return l / r, true
+	case ast.PERCENT:
+		if r == 0 || r == -1 {
+			return 0, r == -1
+		}
+		return l % r, true
+	}
+	return 0, false
+}
+
+// dividesByZero reports whether operator divides by zero, including raising
+// zero to a negative power. It is used to reject such operations in strict
+// math mode.
+func dividesByZero(operator ast.TokenType, left, right any) bool {
+	switch operator {
+	case ast.SLASH, ast.TILDE_SLASH, ast.PERCENT:
+		c, ok := compareNumbers(right, int64(0))
+		return ok && c == 0
+	case ast.STAR_STAR:
+		base, ok := compareNumbers(left, int64(0))
+		exponent, _ := compareNumbers(right, int64(0))
+		return ok && base == 0 && exponent < 0
+	}
+	return false
+}
+
+// formatFloat formats f with the shortest digits that read back as f.
+// Integral values have no fractional part, and infinities and NaN are
+// written Infinity, -Infinity and NaN. As in JavaScript, magnitudes of 1e21
+// and above or below 1e-6 use exponent notation, such as 1e+21, so a large
+// float cannot be mistaken for an exact integer.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	if abs := math.Abs(f); abs >= 1e21 || (abs != 0 && abs < 1e-6) {
+		text := strconv.FormatFloat(f, 'e', -1, 64)
+		return strings.NewReplacer("e+0", "e+", "e-0", "e-").Replace(text)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// add returns the sum of two numbers, for natives that step through values.
+func add(a, b any) any {
+	return arithmetic(&ast.Token{Type: ast.PLUS, Lexeme: "+"}, a, b)
+}
+
+// negate implements unary minus.
+func negate(value any) (any, bool) {
+	switch v := value.(type) {
+	case int64:
+		if v == math.MinInt64 {
+			return new(big.Int).Neg(big.NewInt(v)), true
+		}
+		return -v, true
+	case *big.Int:
+		return normalize(new(big.Int).Neg(v)), true
+	case float64:
+		return -v, true
+	}
+	return nil, false
+}
+
+func bitwise(operator *ast.Token, left, right any) any {
+	l, lok := toBig(left)
+	r, rok := toBig(right)
+	if !lok || !rok {
+		return &RuntimeError{
+			token:   operator,
+			message: "Operands must be integers.",
+		}
+	}
+
+	result := new(big.Int)
+	switch operator.Type {
+	case ast.AMPERSAND:
+		return normalize(result.And(l, r))
+	case ast.PIPE:
+		return normalize(result.Or(l, r))
+	case ast.CARET:
+		return normalize(result.Xor(l, r))
+	}
+
+	if r.Sign() < 0 {
+		return &RuntimeError{
+			token:   operator,
+			message: "Shift count must not be negative.",
+		}
+	}
+	if operator.Type == ast.LESS_LESS {
+		if l.Sign() != 0 && (!r.IsInt64() || r.Int64() > int64(maxIntegerBits-l.BitLen())) {
+			return &RuntimeError{token: operator, message: "Shift count is too large."}
+		}
+		return normalize(result.Lsh(l, uint(r.Int64())))
+	}
+	if !r.IsInt64() || r.Int64() > int64(l.BitLen()) {
+		if l.Sign() < 0 {
+			return int64(-1)
+		}
+		return int64(0)
+	}
+	return normalize(result.Rsh(l, uint(r.Int64())))
+}
+
+// parseInteger parses the digits of an integer literal in base, without a
+// prefix or digit separators.
+func parseInteger(digits string, base int) (any, bool) {
+	if n, err := strconv.ParseInt(digits, base, 64); err == nil {
+		return n, true
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, false
+	}
+	return normalize(n), true
+}
+
+// parseNumber parses the text of a number as accepted by int() and
+// float() and by the string toNumber() method: an optional sign followed by
+// a decimal integer or float, or a 0x, 0b or 0o prefixed integer.
+func parseNumber(text string) (any, bool) {
+	text = strings.TrimSpace(text)
+	sign, digits := "", text
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+		if base != 10 {
+			digits = digits[2:]
+		}
+	}
+	if digits == "" || digits[0] == '+' || digits[0] == '-' {
+		return nil, false
+	}
+	if n, ok := parseInteger(sign+digits, base); ok {
+		return n, true
+	}
+	if base != 10 {
+		return nil, false
+	}
+
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, false
+	}
+	return f, true
+}
+
+// nativeInt converts a number or numeric string to an integer, truncating
+// any fractional part.
+func nativeInt(i *Interpreter, args []any) (any, error) {
+	value := args[0]
+	if s, ok := value.(string); ok {
+		n, ok := parseNumber(s)
+		if !ok {
+			return nil, &nativeError{message: "Cannot convert '" + s + "' to an integer."}
+		}
+		value = n
+	}
+
+	switch v := value.(type) {
+	case int64, *big.Int:
+		return v, nil
+	case float64:
+		if n, ok := truncate(v); ok {
+			return n, nil
+		}
+		return nil, &nativeError{message: "Cannot convert " + stringify(v) + " to an integer."}
+	}
+	return nil, &nativeError{message: "Argument to int() must be a number or string."}
+}
+
+// nativeFloat converts a number or numeric string to a float.
+func nativeFloat(i *Interpreter, args []any) (any, error) {
+	value := args[0]
+	if s, ok := value.(string); ok {
+		n, ok := parseNumber(s)
+		if !ok {
+			return nil, &nativeError{message: "Cannot convert '" + s + "' to a float."}
+		}
+		value = n
+	}
+
+	if f, ok := toFloat(value); ok {
+		return f, nil
+	}
+	return nil, &nativeError{message: "Argument to float() must be a number or string."}
+}
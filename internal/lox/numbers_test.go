@@ -0,0 +1,198 @@
+package lox
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const minInt64 = "(-9223372036854775807 - 1)"
+
+func TestIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{minInt64, "-9223372036854775808"},
+		{"-" + minInt64, "9223372036854775808"},
+		{minInt64 + " ~/ -1", "9223372036854775808"},
+		{minInt64 + " % -1", "0"},
+		{minInt64 + " * -1", "9223372036854775808"},
+		{minInt64 + " - 1", "-9223372036854775809"},
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"9223372036854775807 + 1 - 1", "9223372036854775807"},
+		{"2 ** 64", "18446744073709551616"},
+		{"1 << 64 >> 64", "1"},
+		{"-7 ~/ 2", "-3"},
+		{"-7 % 2", "-1"},
+		{"7 / 2", "3.5"},
+		{"1 / 0", "Infinity"},
+		{"-1 / 0", "-Infinity"},
+	}
+
+	i := NewInterpreter()
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestIntegerResultsAreNormalized(t *testing.T) {
+	i := NewInterpreter()
+	if got, ok := eval(t, i, "-"+minInt64+" - 1").(int64); !ok || got != math.MaxInt64 {
+		t.Errorf("-MinInt64 - 1 = %v (%T), want int64 MaxInt64", got, got)
+	}
+	if got, ok := eval(t, i, minInt64+" ~/ -1").(*big.Int); !ok || got.String() != "9223372036854775808" {
+		t.Errorf("MinInt64 ~/ -1 = %v (%T), want *big.Int", got, got)
+	}
+}
+
+func TestNumberLimits(t *testing.T) {
+	tests := []string{
+		"(3 ** 100000) ** 1000000",
+		"2 ** 1048576",
+		"1 << 1048576",
+		"(1 << 1000000) << 100000",
+		"1 << -1",
+		"1 ~/ 0",
+		"1 % 0",
+	}
+
+	i := NewInterpreter()
+	for _, source := range tests {
+		if err := run(t, i, "var result = "+source+";"); err == nil {
+			t.Errorf("%s succeeded, want an error", source)
+		}
+	}
+}
+
+func TestNumberEquality(t *testing.T) {
+	huge, _ := new(big.Int).SetString("18446744073709551616", 10)
+	other, _ := new(big.Int).SetString("18446744073709551617", 10)
+
+	tests := []struct {
+		a, b any
+		want bool
+	}{
+		{int64(1), float64(1), true},
+		{int64(1), float64(1.5), false},
+		{float64(0), math.Copysign(0, -1), true},
+		{huge, float64(1 << 64), true},
+		{huge, new(big.Int).Set(huge), true},
+		{huge, other, false},
+		{other, float64(1 << 64), false},
+		{int64(math.MaxInt64), float64(math.MaxInt64), false},
+		{int64(1 << 53), float64(1 << 53), true},
+		{int64(1<<53 + 1), float64(1 << 53), false},
+		{math.NaN(), math.NaN(), false},
+		{math.Inf(1), math.Inf(1), true},
+		{int64(1), "1", false},
+		{nil, nil, true},
+	}
+
+	for _, test := range tests {
+		if got := isEqual(test.a, test.b); got != test.want {
+			t.Errorf("isEqual(%v, %v) = %v, want %v", test.a, test.b, got, test.want)
+		}
+		if got := isEqual(test.b, test.a); got != test.want {
+			t.Errorf("isEqual(%v, %v) = %v, want %v", test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestMapKeyNormalization(t *testing.T) {
+	tests := []struct {
+		set, get string
+	}{
+		{"1", "1.0"},
+		{"1.0", "1"},
+		{"0", "-0.0"},
+		{"2 ** 64", "18446744073709551616.0"},
+		{"18446744073709551616", "2 ** 64"},
+		{"-" + minInt64, "9223372036854775808"},
+		{"1.5", "3 / 2"},
+	}
+
+	i := NewInterpreter()
+	for _, test := range tests {
+		source := "{" + test.set + ": true}[" + test.get + "]"
+		if got := eval(t, i, source); got != true {
+			t.Errorf("key %s looked up by %s = %v, want true", test.set, test.get, got)
+		}
+	}
+
+	if got := eval(t, i, `{1: "a", 1.0: "b", 2 ** 64: "c", 18446744073709551616.0: "d"}.len()`); got != int64(2) {
+		t.Errorf("map with equal keys has %v entries, want 2", got)
+	}
+	if err := run(t, i, "var result = {0 / 0: 1};"); err == nil {
+		t.Error("NaN map key succeeded, want an error")
+	}
+}
+
+func TestDivisionByZero(t *testing.T) {
+	tests := []struct {
+		source string
+		ieee   string
+	}{
+		{"1 / 0", "Infinity"},
+		{"-1 / 0", "-Infinity"},
+		{"0 / 0", "NaN"},
+		{"1.5 / 0", "Infinity"},
+		{"1 % 0.0", "NaN"},
+		{"5 ~/ 0.0", "Infinity"},
+	}
+
+	ieee := NewInterpreter()
+	strict := NewInterpreter()
+	strict.SetStrictMath(true)
+	for _, test := range tests {
+		if got := stringify(eval(t, ieee, test.source)); got != test.ieee {
+			t.Errorf("%s = %s, want %s", test.source, got, test.ieee)
+		}
+		err := run(t, strict, "var result = "+test.source+";")
+		if err == nil || !strings.Contains(err.Error(), "Division by zero.") {
+			t.Errorf("strict %s error = %v, want division by zero", test.source, err)
+		}
+	}
+
+	// Integer division and remainder have no IEEE result, so they fail in
+	// both modes.
+	for _, source := range []string{"1 ~/ 0", "1 % 0"} {
+		if err := run(t, ieee, "var result = "+source+";"); err == nil {
+			t.Errorf("%s succeeded, want division by zero", source)
+		}
+	}
+}
+
+func TestNaNAndFloatFormatting(t *testing.T) {
+	tests := []struct {
+		source string
+		want   any
+	}{
+		{"0 / 0 == 0 / 0", false},
+		{"0 / 0 != 0 / 0", true},
+		{"1 / 0 == 1 / 0", true},
+		{`"${1.0}"`, "1"},
+		{`"${2.5}"`, "2.5"},
+		{`"${-0.0}"`, "-0"},
+		{`"${0.1 + 0.2}"`, "0.30000000000000004"},
+		{`"${2.0 ** 70}"`, "1.1805916207174113e+21"},
+		{`"${2.0 ** 1000}"`, "1.0715086071862673e+301"},
+		{`"${-(10.0 ** 21)}"`, "-1e+21"},
+		{`"${10.0 ** 21 / 10}"`, "100000000000000000000"},
+		{`"${1 / 10000000}"`, "1e-7"},
+		{`"${1 / 100000000}"`, "1e-8"},
+		{`"${1 / 1000000}"`, "0.000001"},
+		{`"${1.5 / 1000000}"`, "0.0000015"},
+		{`"${2 ** 70}"`, "1180591620717411303424"},
+	}
+
+	i := NewInterpreter()
+	for _, test := range tests {
+		if got := eval(t, i, test.source); got != test.want {
+			t.Errorf("%s = %v, want %v", test.source, got, test.want)
+		}
+	}
+}
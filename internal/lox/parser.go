@@ -5,10 +5,20 @@ import (
 	"github.com/LucDeCaf/go-lox/internal/lox/ast"
 )
 
+const maxArguments = 255
+
 type Parser struct {
 	tokens  []ast.Token
 	errors  []error
 	current int
+
+	// loopDepth counts the loops enclosing the statement being parsed, so
+	// that break and continue can be rejected outside of them. It is reset
+	// inside function bodies.
+	loopDepth int
+	// functionDepth counts the enclosing functions, so that return can be
+	// rejected in top-level code.
+	functionDepth int
 }
 
 type ParseError struct {
@@ -30,6 +40,7 @@ func NewParser() *Parser {
 func (p *Parser) parse(tokens []ast.Token) ([]ast.Stmt, bool) {
 	p.tokens = tokens
 	p.errors = []error{}
+	p.loopDepth = 0
 
 	statements := []ast.Stmt{}
 
@@ -66,6 +77,14 @@ func (p *Parser) varDeclaration() (ast.Stmt, error) {
 	var value ast.Expr = nil
 	if p.match(ast.EQUAL) {
 		value, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = p.consume(ast.SEMICOLON, "Expect ';' after variable declaration.")
+	if err != nil {
+		return nil, err
 	}
 
 	return &ast.VarStmt{
@@ -74,14 +93,242 @@ func (p *Parser) varDeclaration() (ast.Stmt, error) {
 	}, nil
 }
 
+// statement parses a statement. A '{' at the start of a statement always
+// begins a block, so a map literal used as an expression statement must be
+// wrapped in parentheses.
 func (p *Parser) statement() (ast.Stmt, error) {
 	if p.match(ast.PRINT) {
 		return p.printStmt()
 	}
+	if p.match(ast.FOR) {
+		return p.forInStmt()
+	}
+	if p.match(ast.BREAK, ast.CONTINUE) {
+		return p.loopControlStmt()
+	}
+	if p.match(ast.RETURN) {
+		return p.returnStmt()
+	}
+	if p.match(ast.THROW) {
+		return p.throwStmt()
+	}
+	if p.match(ast.TRY) {
+		return p.tryStmt()
+	}
+	if p.match(ast.IMPORT) {
+		return p.importStmt()
+	}
+	if p.match(ast.LEFT_BRACE) {
+		statements, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.BlockStmt{Statements: statements}, nil
+	}
 
 	return p.expressionStmt()
 }
 
+func (p *Parser) block() ([]ast.Stmt, error) {
+	statements := []ast.Stmt{}
+
+	for !p.check(ast.RIGHT_BRACE) && !p.isAtEnd() {
+		stmt, err := p.declaration()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, stmt)
+	}
+
+	_, err := p.consume(ast.RIGHT_BRACE, "Expect '}' after block.")
+	if err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+func (p *Parser) forInStmt() (ast.Stmt, error) {
+	_, err := p.consume(ast.LEFT_PAREN, "Expect '(' after 'for'.")
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := p.consume(ast.IDENTIFIER, "Expect loop variable name.")
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := p.consume(ast.IN, "Expect 'in' after loop variable.")
+	if err != nil {
+		return nil, err
+	}
+
+	iterable, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(ast.RIGHT_PAREN, "Expect ')' after for clauses.")
+	if err != nil {
+		return nil, err
+	}
+
+	p.loopDepth++
+	body, err := p.statement()
+	p.loopDepth--
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ForInStmt{
+		Name:     name,
+		In:       in,
+		Iterable: iterable,
+		Body:     body,
+	}, nil
+}
+
+func (p *Parser) loopControlStmt() (ast.Stmt, error) {
+	keyword := p.previous()
+	if p.loopDepth == 0 {
+		return nil, &ParseError{
+			token:   *keyword,
+			message: fmt.Sprintf("Can't use '%s' outside of a loop.", keyword.Lexeme),
+		}
+	}
+
+	_, err := p.consume(ast.SEMICOLON, fmt.Sprintf("Expect ';' after '%s'.", keyword.Lexeme))
+	if err != nil {
+		return nil, err
+	}
+
+	if keyword.Type == ast.BREAK {
+		return &ast.BreakStmt{Keyword: keyword}, nil
+	}
+	return &ast.ContinueStmt{Keyword: keyword}, nil
+}
+
+func (p *Parser) returnStmt() (ast.Stmt, error) {
+	keyword := p.previous()
+	if p.functionDepth == 0 {
+		return nil, &ParseError{token: *keyword, message: "Can't return from top-level code."}
+	}
+
+	var value ast.Expr
+	if !p.check(ast.SEMICOLON) {
+		var err error
+		value, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := p.consume(ast.SEMICOLON, "Expect ';' after return value.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ReturnStmt{Keyword: keyword, Value: value}, nil
+}
+
+func (p *Parser) throwStmt() (ast.Stmt, error) {
+	keyword := p.previous()
+
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(ast.SEMICOLON, "Expect ';' after thrown value.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ThrowStmt{Keyword: keyword, Value: value}, nil
+}
+
+func (p *Parser) tryStmt() (ast.Stmt, error) {
+	stmt := &ast.TryStmt{}
+
+	_, err := p.consume(ast.LEFT_BRACE, "Expect '{' after 'try'.")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Body, err = p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(ast.CATCH) {
+		_, err = p.consume(ast.LEFT_PAREN, "Expect '(' after 'catch'.")
+		if err != nil {
+			return nil, err
+		}
+		stmt.CatchName, err = p.consume(ast.IDENTIFIER, "Expect error variable name.")
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(ast.RIGHT_PAREN, "Expect ')' after error variable.")
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.consume(ast.LEFT_BRACE, "Expect '{' before catch body.")
+		if err != nil {
+			return nil, err
+		}
+		stmt.CatchBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.match(ast.FINALLY) {
+		_, err = p.consume(ast.LEFT_BRACE, "Expect '{' after 'finally'.")
+		if err != nil {
+			return nil, err
+		}
+		stmt.FinallyBody, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.CatchName == nil && stmt.FinallyBody == nil {
+		return nil, &ParseError{token: *p.peek(), message: "Expect 'catch' or 'finally' after try block."}
+	}
+
+	return stmt, nil
+}
+
+// importStmt parses import "path" as name. "as" is not reserved, so it is
+// matched as an identifier.
+func (p *Parser) importStmt() (ast.Stmt, error) {
+	keyword := p.previous()
+
+	path, err := p.consume(ast.STRING, "Expect module path string after 'import'.")
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.check(ast.IDENTIFIER) || p.peek().Lexeme != "as" {
+		return nil, &ParseError{token: *p.peek(), message: "Expect 'as' after module path."}
+	}
+	p.advance()
+
+	name, err := p.consume(ast.IDENTIFIER, "Expect module name after 'as'.")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.consume(ast.SEMICOLON, "Expect ';' after import.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ImportStmt{Keyword: keyword, Path: path, Name: name}, nil
+}
+
 func (p *Parser) printStmt() (ast.Stmt, error) {
 	expr, err := p.expression()
 	if err != nil {
@@ -110,8 +357,146 @@ func (p *Parser) expressionStmt() (ast.Stmt, error) {
 	return &ast.ExpressionStmt{Expression: expr}, nil
 }
 
+// expression parses an expression. Operators bind, from loosest to tightest:
+//
+//	= += -= *= /= %=  right-associative
+//	? :               right-associative
+//	??
+//	== !=
+//	< <= > >=
+//	|
+//	^
+//	&
+//	<< >>
+//	+ -
+//	* / ~/ %
+//	! - ~ ++ --       prefix
+//	**                right-associative
+//	++ --             postfix
+//	calls, indexing and property access
+//
+// Since ** binds more tightly than prefix operators, -2 ** 2 is -(2 ** 2),
+// while its right operand may itself be negated, as in 2 ** -1.
 func (p *Parser) expression() (ast.Expr, error) {
-	return p.equality()
+	return p.assignment()
+}
+
+func (p *Parser) assignment() (ast.Expr, error) {
+	expr, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(ast.EQUAL) {
+		equals := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+
+		switch target := expr.(type) {
+		case *ast.VariableExpr:
+			return &ast.AssignExpr{
+				Name:  target.Name,
+				Value: value,
+			}, nil
+		case *ast.IndexExpr:
+			return &ast.IndexSetExpr{
+				Object:  target.Object,
+				Bracket: target.Bracket,
+				Index:   target.Index,
+				Value:   value,
+			}, nil
+		}
+
+		return nil, &ParseError{token: *equals, message: "Invalid assignment target."}
+	}
+
+	if p.match(ast.PLUS_EQUAL, ast.MINUS_EQUAL, ast.STAR_EQUAL, ast.SLASH_EQUAL, ast.PERCENT_EQUAL) {
+		operator := p.previous()
+		value, err := p.assignment()
+		if err != nil {
+			return nil, err
+		}
+		if !isAssignable(expr) {
+			return nil, &ParseError{token: *operator, message: "Invalid assignment target."}
+		}
+
+		return &ast.CompoundAssignExpr{
+			Target:   expr,
+			Operator: operator,
+			Value:    value,
+		}, nil
+	}
+
+	return expr, nil
+}
+
+// isAssignable reports whether expr can be the target of a compound
+// assignment, increment or decrement.
+func isAssignable(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.VariableExpr, *ast.IndexExpr:
+		return true
+	}
+	return false
+}
+
+// conditional parses cond ? a : b, which binds more loosely than everything
+// but assignment and is right-associative.
+func (p *Parser) conditional() (ast.Expr, error) {
+	expr, err := p.coalesce()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(ast.QUESTION) {
+		thenBranch, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(ast.COLON, "Expect ':' after then branch of conditional expression.")
+		if err != nil {
+			return nil, err
+		}
+
+		elseBranch, err := p.conditional()
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.ConditionalExpr{
+			Condition:  expr,
+			ThenBranch: thenBranch,
+			ElseBranch: elseBranch,
+		}, nil
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) coalesce() (ast.Expr, error) {
+	expr, err := p.equality()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(ast.QUESTION_QUESTION) {
+		operator := p.previous()
+		right, err := p.equality()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.LogicalExpr{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
 }
 
 func (p *Parser) equality() (ast.Expr, error) {
@@ -138,12 +523,104 @@ func (p *Parser) equality() (ast.Expr, error) {
 }
 
 func (p *Parser) comparison() (ast.Expr, error) {
-	expr, err := p.term()
+	expr, err := p.bitwiseOr()
 	if err != nil {
 		return nil, err
 	}
 
 	for p.match(ast.GREATER, ast.GREATER_EQUAL, ast.LESS, ast.LESS_EQUAL) {
+		operator := p.previous()
+		right, err := p.bitwiseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.BinaryExpr{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) bitwiseOr() (ast.Expr, error) {
+	expr, err := p.bitwiseXor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(ast.PIPE) {
+		operator := p.previous()
+		right, err := p.bitwiseXor()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.BinaryExpr{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) bitwiseXor() (ast.Expr, error) {
+	expr, err := p.bitwiseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(ast.CARET) {
+		operator := p.previous()
+		right, err := p.bitwiseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.BinaryExpr{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) bitwiseAnd() (ast.Expr, error) {
+	expr, err := p.shift()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(ast.AMPERSAND) {
+		operator := p.previous()
+		right, err := p.shift()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.BinaryExpr{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) shift() (ast.Expr, error) {
+	expr, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.match(ast.LESS_LESS, ast.GREATER_GREATER) {
 		operator := p.previous()
 		right, err := p.term()
 		if err != nil {
@@ -189,7 +666,7 @@ func (p *Parser) factor() (ast.Expr, error) {
 		return nil, err
 	}
 
-	for p.match(ast.STAR, ast.SLASH) {
+	for p.match(ast.STAR, ast.SLASH, ast.TILDE_SLASH, ast.PERCENT) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -207,7 +684,20 @@ func (p *Parser) factor() (ast.Expr, error) {
 }
 
 func (p *Parser) unary() (ast.Expr, error) {
-	if p.match(ast.BANG, ast.MINUS) {
+	if p.match(ast.PLUS_PLUS, ast.MINUS_MINUS) {
+		operator := p.previous()
+		target, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		if !isAssignable(target) {
+			return nil, &ParseError{token: *operator, message: "Invalid increment or decrement target."}
+		}
+
+		return &ast.UpdateExpr{Target: target, Operator: operator, Prefix: true}, nil
+	}
+
+	if p.match(ast.BANG, ast.MINUS, ast.TILDE) {
 		operator := p.previous()
 		right, err := p.unary()
 		if err != nil {
@@ -220,7 +710,124 @@ func (p *Parser) unary() (ast.Expr, error) {
 		}, nil
 	}
 
-	return p.primary()
+	return p.power()
+}
+
+func (p *Parser) power() (ast.Expr, error) {
+	expr, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(ast.STAR_STAR) {
+		operator := p.previous()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		expr = &ast.BinaryExpr{
+			Left:     expr,
+			Operator: operator,
+			Right:    right,
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) postfix() (ast.Expr, error) {
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.match(ast.PLUS_PLUS, ast.MINUS_MINUS) {
+		operator := p.previous()
+		if !isAssignable(expr) {
+			return nil, &ParseError{token: *operator, message: "Invalid increment or decrement target."}
+		}
+
+		return &ast.UpdateExpr{Target: expr, Operator: operator}, nil
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) call() (ast.Expr, error) {
+	expr, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	optional := false
+	for {
+		if p.match(ast.LEFT_PAREN) {
+			expr, err = p.finishCall(expr)
+			if err != nil {
+				return nil, err
+			}
+		} else if p.match(ast.DOT, ast.QUESTION_DOT) {
+			get := &ast.GetExpr{Object: expr, Optional: p.previous().Type == ast.QUESTION_DOT}
+			get.Name, err = p.consume(ast.IDENTIFIER, "Expect property name after '.'.")
+			if err != nil {
+				return nil, err
+			}
+			optional = optional || get.Optional
+			expr = get
+		} else if p.match(ast.LEFT_BRACKET) {
+			bracket := p.previous()
+			index, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			_, err = p.consume(ast.RIGHT_BRACKET, "Expect ']' after index.")
+			if err != nil {
+				return nil, err
+			}
+			expr = &ast.IndexExpr{Object: expr, Bracket: bracket, Index: index}
+		} else {
+			break
+		}
+	}
+
+	if optional {
+		return &ast.OptionalChainExpr{Expression: expr}, nil
+	}
+	return expr, nil
+}
+
+func (p *Parser) finishCall(callee ast.Expr) (ast.Expr, error) {
+	arguments := []ast.Expr{}
+
+	if !p.check(ast.RIGHT_PAREN) {
+		for {
+			if len(arguments) >= maxArguments {
+				return nil, &ParseError{token: *p.peek(), message: "Can't have more than 255 arguments."}
+			}
+
+			arg, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			arguments = append(arguments, arg)
+
+			if !p.match(ast.COMMA) {
+				break
+			}
+		}
+	}
+
+	paren, err := p.consume(ast.RIGHT_PAREN, "Expect ')' after arguments.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.CallExpr{
+		Callee:    callee,
+		Paren:     paren,
+		Arguments: arguments,
+	}, nil
 }
 
 func (p *Parser) primary() (ast.Expr, error) {
@@ -243,6 +850,27 @@ func (p *Parser) primary() (ast.Expr, error) {
 		return &ast.LiteralExpr{Value: value}, nil
 	}
 
+	if p.match(ast.INTERPOLATION) {
+		return p.interpolation()
+	}
+
+	if p.match(ast.LEFT_BRACKET) {
+		return p.list()
+	}
+
+	if p.match(ast.FUN) {
+		return p.function()
+	}
+
+	if p.check(ast.LEFT_PAREN) && p.isArrowFunction() {
+		p.advance()
+		return p.arrowFunction()
+	}
+
+	if p.match(ast.LEFT_BRACE) {
+		return p.mapLiteral()
+	}
+
 	if p.match(ast.LEFT_PAREN) {
 		expr, err := p.expression()
 		if err != nil {
@@ -260,6 +888,216 @@ func (p *Parser) primary() (ast.Expr, error) {
 	return nil, &ParseError{token: *p.peek(), message: "Invalid token."}
 }
 
+// function parses the rest of an anonymous function after 'fun'.
+func (p *Parser) function() (ast.Expr, error) {
+	keyword := p.previous()
+	var name *ast.Token
+	if p.match(ast.IDENTIFIER) {
+		name = p.previous()
+	}
+	_, err := p.consume(ast.LEFT_PAREN, "Expect '(' after 'fun'.")
+	if err != nil {
+		return nil, err
+	}
+	params, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+	_, err = p.consume(ast.LEFT_BRACE, "Expect '{' before function body.")
+	if err != nil {
+		return nil, err
+	}
+
+	var body []ast.Stmt
+	err = p.functionBody(func() error {
+		body, err = p.block()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.FunctionExpr{Keyword: keyword, Name: name, Params: params, Body: body}, nil
+}
+
+// arrowFunction parses the rest of (params) => expr after the '('.
+func (p *Parser) arrowFunction() (ast.Expr, error) {
+	params, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+	arrow, err := p.consume(ast.ARROW, "Expect '=>' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+
+	var value ast.Expr
+	err = p.functionBody(func() error {
+		value, err = p.expression()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.FunctionExpr{
+		Keyword: arrow,
+		Params:  params,
+		Body:    []ast.Stmt{&ast.ReturnStmt{Keyword: arrow, Value: value}},
+	}, nil
+}
+
+// isArrowFunction reports whether the '(' at the current token begins the
+// parameter list of an arrow function rather than a grouping.
+func (p *Parser) isArrowFunction() bool {
+	idx := p.current + 1
+	if idx < len(p.tokens) && p.tokens[idx].Type != ast.RIGHT_PAREN {
+		for idx < len(p.tokens) && p.tokens[idx].Type == ast.IDENTIFIER {
+			idx++
+			if idx >= len(p.tokens) || p.tokens[idx].Type != ast.COMMA {
+				break
+			}
+			idx++
+		}
+	}
+	return idx+1 < len(p.tokens) && p.tokens[idx].Type == ast.RIGHT_PAREN && p.tokens[idx+1].Type == ast.ARROW
+}
+
+// parameters parses a parameter list after its opening '('.
+func (p *Parser) parameters() ([]*ast.Token, error) {
+	params := []*ast.Token{}
+	if !p.check(ast.RIGHT_PAREN) {
+		for {
+			if len(params) >= maxArguments {
+				return nil, &ParseError{token: *p.peek(), message: "Can't have more than 255 parameters."}
+			}
+
+			param, err := p.consume(ast.IDENTIFIER, "Expect parameter name.")
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+
+			if !p.match(ast.COMMA) {
+				break
+			}
+		}
+	}
+
+	_, err := p.consume(ast.RIGHT_PAREN, "Expect ')' after parameters.")
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// functionBody runs parse for the body of a function, where return is
+// allowed and break and continue no longer refer to enclosing loops.
+func (p *Parser) functionBody(parse func() error) error {
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
+	p.functionDepth++
+	defer func() {
+		p.loopDepth = loopDepth
+		p.functionDepth--
+	}()
+
+	return parse()
+}
+
+func (p *Parser) list() (ast.Expr, error) {
+	elements := []ast.Expr{}
+
+	for !p.check(ast.RIGHT_BRACKET) {
+		element, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+
+		if !p.match(ast.COMMA) {
+			break
+		}
+	}
+
+	_, err := p.consume(ast.RIGHT_BRACKET, "Expect ']' after list elements.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.ListExpr{Elements: elements}, nil
+}
+
+// mapLiteral parses the entries of a {key: value, ...} literal. It is only
+// reached in expression position; see statement.
+func (p *Parser) mapLiteral() (ast.Expr, error) {
+	brace := p.previous()
+	keys := []ast.Expr{}
+	values := []ast.Expr{}
+
+	for !p.check(ast.RIGHT_BRACE) {
+		key, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.consume(ast.COLON, "Expect ':' after map key.")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+
+		if !p.match(ast.COMMA) {
+			break
+		}
+	}
+
+	_, err := p.consume(ast.RIGHT_BRACE, "Expect '}' after map entries.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.MapExpr{Brace: brace, Keys: keys, Values: values}, nil
+}
+
+func (p *Parser) interpolation() (ast.Expr, error) {
+	parts := []ast.Expr{}
+
+	for {
+		if text := p.previous().Literal.(string); text != "" {
+			parts = append(parts, &ast.LiteralExpr{Value: text})
+		}
+
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+
+		if p.match(ast.INTERPOLATION_MIDDLE) {
+			continue
+		}
+
+		_, err = p.consume(ast.INTERPOLATION_END, "Expect '}' after interpolated expression.")
+		if err != nil {
+			return nil, err
+		}
+
+		if text := p.previous().Literal.(string); text != "" {
+			parts = append(parts, &ast.LiteralExpr{Value: text})
+		}
+
+		return &ast.InterpolationExpr{Parts: parts}, nil
+	}
+}
+
 func (p *Parser) synchronize() {
 	p.advance()
 
@@ -285,6 +1123,16 @@ func (p *Parser) synchronize() {
 			return
 		case ast.RETURN:
 			return
+		case ast.BREAK:
+			return
+		case ast.CONTINUE:
+			return
+		case ast.THROW:
+			return
+		case ast.TRY:
+			return
+		case ast.IMPORT:
+			return
 		}
 
 		p.advance()
@@ -0,0 +1,102 @@
+package lox
+
+import (
+	"fmt"
+	"github.com/LucDeCaf/go-lox/internal/lox/ast"
+	"testing"
+)
+
+// parseExpression parses source as a single expression statement and
+// prints it with the AstPrinter.
+func parseExpression(t *testing.T, source string) string {
+	t.Helper()
+
+	tokens, ok := NewScanner().scanTokens(source + ";")
+	if !ok {
+		t.Fatalf("scan %q failed", source)
+	}
+	parser := NewParser()
+	statements, ok := parser.parse(tokens)
+	if !ok || len(statements) != 1 {
+		t.Fatalf("parse %q: %v", source, parser.errors)
+	}
+	stmt, ok := statements[0].(*ast.ExpressionStmt)
+	if !ok {
+		t.Fatalf("parse %q: got %T, want an expression statement", source, statements[0])
+	}
+	return (&AstPrinter{}).Print(stmt.Expression)
+}
+
+func TestBitwisePrecedence(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"1 | 2 ^ 3 & 4", "(1 (2 (3 4 &) ^) |)"},
+		{"1 & 2 | 3", "((1 2 &) 3 |)"},
+		{"1 | 2 | 3", "((1 2 |) 3 |)"},
+		{"1 << 2 + 3", "(1 (2 3 +) <<)"},
+		{"1 << 2 >> 3", "((1 2 <<) 3 >>)"},
+		{"1 & 2 << 3", "(1 (2 3 <<) &)"},
+		{"1 == 2 | 3", "(1 (2 3 |) ==)"},
+	}
+
+	for _, test := range tests {
+		if got := parseExpression(t, test.source); got != test.want {
+			t.Errorf("%s parsed as %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestInterpolationParts(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"a${x}b"`, "(interpolate 'a' x 'b')"},
+		{`"${x + 1}"`, "(interpolate (x 1 +))"},
+		{`"plain"`, "'plain'"},
+	}
+
+	for _, test := range tests {
+		if got := parseExpression(t, test.source); got != test.want {
+			t.Errorf("%s parsed as %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestSynchronizeAtStatementKeywords(t *testing.T) {
+	// Each source has a syntax error on line 1 followed, without a
+	// semicolon, by a statement the parser must resume at.
+	tests := []struct {
+		source string
+		lines  []int
+	}{
+		{"print 1 2\ntry { print 3; } catch (e) {}", []int{1}},
+		{"print 1 2\nthrow (;", []int{1, 2}},
+		{"print 1 2\nimport x;", []int{1, 2}},
+		{"print 1 2\nbreak;", []int{1, 2}},
+		{"print 1 2\ncontinue;", []int{1, 2}},
+		{"print 1 2\nfor (x in [1]) { print x; }", []int{1}},
+	}
+
+	for _, test := range tests {
+		tokens, ok := NewScanner().scanTokens(test.source)
+		if !ok {
+			t.Fatalf("scan %q failed", test.source)
+		}
+		parser := NewParser()
+		if _, ok := parser.parse(tokens); ok {
+			t.Errorf("%q parsed, want a syntax error", test.source)
+			continue
+		}
+
+		lines := make([]int, len(parser.errors))
+		for idx, err := range parser.errors {
+			lines[idx] = syntaxErrorLine(err)
+		}
+		if fmt.Sprint(lines) != fmt.Sprint(test.lines) {
+			t.Errorf("%q: errors %v on lines %v, want lines %v", test.source, parser.errors, lines, test.lines)
+		}
+	}
+}
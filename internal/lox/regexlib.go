@@ -0,0 +1,140 @@
+package lox
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// LoxRegex is a compiled regular expression, created by regex(pattern).
+// Patterns use Go's RE2 syntax.
+type LoxRegex struct {
+	re *regexp.Regexp
+}
+
+func nativeRegex(i *Interpreter, args []any) (any, error) {
+	pattern, ok := args[0].(string)
+	if !ok {
+		return nil, &nativeError{message: "Regex pattern must be a string."}
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &nativeError{message: "Invalid regex: " + err.Error()}
+	}
+	return &LoxRegex{re: re}, nil
+}
+
+func (r *LoxRegex) String() string {
+	return "<regex /" + r.re.String() + "/>"
+}
+
+func (r *LoxRegex) get(name string) (any, bool) {
+	switch name {
+	case "pattern":
+		return r.re.String(), true
+	case "match":
+		return NewNativeFunction(name, 1, r.match), true
+	case "find":
+		return NewNativeFunction(name, 1, r.find), true
+	case "findAll":
+		return NewNativeFunction(name, 1, r.findAll), true
+	case "replace":
+		return NewNativeFunction(name, 2, r.replace), true
+	}
+	return nil, false
+}
+
+// match reports whether the pattern matches anywhere in the string.
+func (r *LoxRegex) match(i *Interpreter, args []any) (any, error) {
+	s, err := stringArg("match", args[0])
+	if err != nil {
+		return nil, err
+	}
+	return r.re.MatchString(s), nil
+}
+
+// find returns the first match as a map, or nil if there is none.
+func (r *LoxRegex) find(i *Interpreter, args []any) (any, error) {
+	s, err := stringArg("find", args[0])
+	if err != nil {
+		return nil, err
+	}
+	loc := r.re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return nil, nil
+	}
+	return r.matchMap(s, loc), nil
+}
+
+func (r *LoxRegex) findAll(i *Interpreter, args []any) (any, error) {
+	s, err := stringArg("findAll", args[0])
+	if err != nil {
+		return nil, err
+	}
+	matches := []any{}
+	for _, loc := range r.re.FindAllStringSubmatchIndex(s, -1) {
+		matches = append(matches, r.matchMap(s, loc))
+	}
+	return NewLoxList(matches), nil
+}
+
+// replace replaces every match. The replacement is either a string, in which
+// $1 or ${name} refer to capture groups, or a function called with each match
+// map whose result is inserted.
+func (r *LoxRegex) replace(i *Interpreter, args []any) (any, error) {
+	s, err := stringArg("replace", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch replacement := args[1].(type) {
+	case string:
+		return r.re.ReplaceAllString(s, replacement), nil
+
+	case LoxCallable:
+		var result strings.Builder
+		last := 0
+		for _, loc := range r.re.FindAllStringSubmatchIndex(s, -1) {
+			value, err := i.call(replacement, []any{r.matchMap(s, loc)})
+			if err != nil {
+				return nil, err
+			}
+			result.WriteString(s[last:loc[0]])
+			result.WriteString(stringify(value))
+			last = loc[1]
+		}
+		result.WriteString(s[last:])
+		return result.String(), nil
+	}
+
+	return nil, &nativeError{message: "Replacement must be a string or a function."}
+}
+
+// matchMap describes a match as a map with the matched "text", its
+// character "index", the list of capture "groups" and the "named" groups.
+// Groups that did not participate in the match are nil.
+func (r *LoxRegex) matchMap(s string, loc []int) *LoxMap {
+	groups := []any{}
+	named := NewLoxMap()
+	for idx, name := range r.re.SubexpNames() {
+		if idx == 0 {
+			continue
+		}
+		var group any
+		if loc[2*idx] >= 0 {
+			group = s[loc[2*idx]:loc[2*idx+1]]
+		}
+		groups = append(groups, group)
+		if name != "" {
+			named.Set(name, group)
+		}
+	}
+
+	m := NewLoxMap()
+	m.Set("text", s[loc[0]:loc[1]])
+	m.Set("index", int64(utf8.RuneCountInString(s[:loc[0]])))
+	m.Set("groups", NewLoxList(groups))
+	m.Set("named", named)
+	return m
+}
@@ -0,0 +1,64 @@
+package lox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegex(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`date.match("due 2024-01-15")`, "true"},
+		{`date.match("no date")`, "false"},
+		{`date.find("no date")`, "nil"},
+		{`date.find("é 2024-01-15")["text"]`, "2024-01-15"},
+		{`date.find("é 2024-01-15")["index"]`, "2"},
+		{`date.find("2024-01-15")["groups"]`, "[2024, 01, 15]"},
+		{`date.find("2024-01-15")["named"]`, "{year: 2024, month: 01, day: 15}"},
+		{`date.findAll("2024-01-15, 2025-12-31").map((m) => m["named"]["year"])`, "[2024, 2025]"},
+		{`date.findAll("none")`, "[]"},
+		{`regex("(a)|(b)").find("b")["groups"]`, "[nil, b]"},
+		{`date.replace("2024-01-15", "$3/$2/$1")`, "15/01/2024"},
+		{`date.replace("2024-01-15", "\${day}.\${month}")`, "15.01"},
+		{`regex("[0-9]+").replace("a1b22", (m) => m["text"].len())`, "a1b2"},
+		{`regex("o").replace("foo", fun (m) { return m["index"]; })`, "f12"},
+		{`date.pattern`, `(?P<year>\d{4})-(?P<month>\d{2})-(?P<day>\d{2})`},
+	}
+
+	i := NewInterpreter()
+	if err := run(t, i, `var date = regex("(?P<year>\\d{4})-(?P<month>\\d{2})-(?P<day>\\d{2})");`); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %s, want %s", test.source, got, test.want)
+		}
+	}
+}
+
+func TestRegexErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"var r =\n  regex(\"(unclosed\");", "Invalid regex"},
+		{"var r =\n  regex(1);", "Regex pattern must be a string."},
+		{"var r =\n  regex(\"a\").replace(\"a\", 1);", "Replacement must be a string or a function."},
+		{"var r =\n  regex(\"a\").replace(\"a\", (m) => nil + 1);", "Operands must be two numbers or two strings."},
+	}
+
+	for _, test := range tests {
+		err := run(t, NewInterpreter(), test.source)
+		var runtimeErr *RuntimeError
+		if !errors.As(err, &runtimeErr) || !strings.Contains(runtimeErr.message, test.want) {
+			t.Errorf("%q error = %v, want a RuntimeError containing %q", test.source, err, test.want)
+			continue
+		}
+		if line := runtimeErr.token.Line; line != 2 {
+			t.Errorf("%q error on line %d, want 2", test.source, line)
+		}
+	}
+}
@@ -4,16 +4,28 @@ import (
 	"fmt"
 	"github.com/LucDeCaf/go-lox/internal/lox/ast"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Scanner struct {
-	source string
+	source []rune
 	tokens []ast.Token
 	errors []error
 
 	start   int
 	current int
 	line    int
+
+	// interpolations holds, for each ${...} currently being scanned, the
+	// number of unclosed '{' seen inside it. A '}' at depth zero resumes
+	// scanning the enclosing string.
+	interpolations []int
+
+	// allowShebang makes the scanner skip a leading "#!" line, as found at
+	// the top of executable scripts.
+	allowShebang bool
 }
 
 type ScanError struct {
@@ -27,24 +39,39 @@ func (e *ScanError) Error() string {
 
 func NewScanner() *Scanner {
 	return &Scanner{
-		source:  "",
+		source:  []rune{},
 		tokens:  []ast.Token{},
 		errors:  []error{},
 		start:   0,
 		current: 0,
 		line:    1,
+
+		interpolations: []int{},
 	}
 }
 
 func (s *Scanner) scanTokens(source string) ([]ast.Token, bool) {
-	s.source = source
+	s.source = []rune(source)
 	s.errors = []error{}
 
+	if s.allowShebang && strings.HasPrefix(source, "#!") {
+		for s.peek() != '\n' && !s.isAtEnd() {
+			s.advance()
+		}
+	}
+
 	for !s.isAtEnd() {
 		s.start = s.current
 		s.scanToken()
 	}
 
+	if len(s.interpolations) > 0 {
+		s.errors = append(s.errors, &ScanError{
+			line:    s.line,
+			message: "Unterminated string interpolation.",
+		})
+	}
+
 	s.tokens = append(s.tokens, ast.NewToken(
 		ast.EOF,
 		"",
@@ -63,21 +90,76 @@ func (s *Scanner) scanToken() {
 	case ')':
 		s.addToken(ast.RIGHT_PAREN)
 	case '{':
+		if n := len(s.interpolations); n > 0 {
+			s.interpolations[n-1]++
+		}
 		s.addToken(ast.LEFT_BRACE)
 	case '}':
+		if n := len(s.interpolations); n > 0 {
+			if s.interpolations[n-1] == 0 {
+				s.interpolations = s.interpolations[:n-1]
+				if err := s.parseString(true); err != nil {
+					s.errors = append(s.errors, err)
+				}
+				break
+			}
+			s.interpolations[n-1]--
+		}
 		s.addToken(ast.RIGHT_BRACE)
+	case '[':
+		s.addToken(ast.LEFT_BRACKET)
+	case ']':
+		s.addToken(ast.RIGHT_BRACKET)
 	case ',':
 		s.addToken(ast.COMMA)
+	case ':':
+		s.addToken(ast.COLON)
 	case '.':
 		s.addToken(ast.DOT)
 	case '-':
-		s.addToken(ast.MINUS)
+		if s.match('-') {
+			s.addToken(ast.MINUS_MINUS)
+		} else if s.match('=') {
+			s.addToken(ast.MINUS_EQUAL)
+		} else {
+			s.addToken(ast.MINUS)
+		}
 	case '+':
-		s.addToken(ast.PLUS)
+		if s.match('+') {
+			s.addToken(ast.PLUS_PLUS)
+		} else if s.match('=') {
+			s.addToken(ast.PLUS_EQUAL)
+		} else {
+			s.addToken(ast.PLUS)
+		}
 	case ';':
 		s.addToken(ast.SEMICOLON)
 	case '*':
-		s.addToken(ast.STAR)
+		if s.match('*') {
+			s.addToken(ast.STAR_STAR)
+		} else if s.match('=') {
+			s.addToken(ast.STAR_EQUAL)
+		} else {
+			s.addToken(ast.STAR)
+		}
+	case '%':
+		if s.match('=') {
+			s.addToken(ast.PERCENT_EQUAL)
+		} else {
+			s.addToken(ast.PERCENT)
+		}
+	case '~':
+		if s.match('/') {
+			s.addToken(ast.TILDE_SLASH)
+		} else {
+			s.addToken(ast.TILDE)
+		}
+	case '&':
+		s.addToken(ast.AMPERSAND)
+	case '|':
+		s.addToken(ast.PIPE)
+	case '^':
+		s.addToken(ast.CARET)
 	case '!':
 		if s.match('=') {
 			s.addToken(ast.BANG_EQUAL)
@@ -87,26 +169,46 @@ func (s *Scanner) scanToken() {
 	case '=':
 		if s.match('=') {
 			s.addToken(ast.EQUAL_EQUAL)
+		} else if s.match('>') {
+			s.addToken(ast.ARROW)
 		} else {
 			s.addToken(ast.EQUAL)
 		}
 	case '<':
 		if s.match('=') {
 			s.addToken(ast.LESS_EQUAL)
+		} else if s.match('<') {
+			s.addToken(ast.LESS_LESS)
 		} else {
 			s.addToken(ast.LESS)
 		}
 	case '>':
 		if s.match('=') {
 			s.addToken(ast.GREATER_EQUAL)
+		} else if s.match('>') {
+			s.addToken(ast.GREATER_GREATER)
 		} else {
 			s.addToken(ast.GREATER)
 		}
+	case '?':
+		if s.match('?') {
+			s.addToken(ast.QUESTION_QUESTION)
+		} else if s.match('.') {
+			s.addToken(ast.QUESTION_DOT)
+		} else {
+			s.addToken(ast.QUESTION)
+		}
 	case '/':
 		if s.match('/') {
 			for s.peek() != '\n' && !s.isAtEnd() {
 				s.advance()
 			}
+		} else if s.match('*') {
+			if err := s.blockComment(); err != nil {
+				s.errors = append(s.errors, err)
+			}
+		} else if s.match('=') {
+			s.addToken(ast.SLASH_EQUAL)
 		} else {
 			s.addToken(ast.SLASH)
 		}
@@ -122,13 +224,15 @@ func (s *Scanner) scanToken() {
 		s.line++
 
 	case '"':
-		if err := s.parseString(); err != nil {
+		if err := s.parseString(false); err != nil {
 			s.errors = append(s.errors, err)
 		}
 
 	default:
 		if isDigit(c) {
-			s.parseNumber()
+			if err := s.parseNumber(); err != nil {
+				s.errors = append(s.errors, err)
+			}
 		} else if isAlpha(c) {
 			s.parseIdent()
 		} else {
@@ -145,16 +249,54 @@ func (s *Scanner) addToken(tokenType ast.TokenType) {
 }
 
 func (s *Scanner) addTokenWithLiteral(tokenType ast.TokenType, literal any) {
-	lexeme := s.source[s.start:s.current]
+	lexeme := string(s.source[s.start:s.current])
 	s.tokens = append(s.tokens, ast.NewToken(tokenType, lexeme, literal, s.line))
 }
 
-func (s *Scanner) parseString() error {
+// parseString scans string contents up to the closing quote, emitting a
+// STRING token. If a ${ is found first, an INTERPOLATION token is emitted for
+// the text so far and scanning resumes at the matching '}'.
+//
+// resumed is set when scanning continues after the '}' closing an
+// interpolated expression. The text is then emitted as INTERPOLATION_MIDDLE
+// or INTERPOLATION_END instead, so the parser cannot mistake a string
+// literal inside the expression for the rest of the enclosing string.
+func (s *Scanner) parseString(resumed bool) error {
+	var literal strings.Builder
+	var escapeErr error
+
 	for s.peek() != '"' && !s.isAtEnd() {
-		if s.peek() == '\n' {
+		c := s.advance()
+		switch c {
+		case '\n':
 			s.line++
+			literal.WriteRune(c)
+		case '$':
+			if s.match('{') {
+				s.interpolations = append(s.interpolations, 0)
+				if escapeErr != nil {
+					return escapeErr
+				}
+				tokenType := ast.INTERPOLATION
+				if resumed {
+					tokenType = ast.INTERPOLATION_MIDDLE
+				}
+				s.addTokenWithLiteral(tokenType, literal.String())
+				return nil
+			}
+			literal.WriteRune(c)
+		case '\\':
+			r, err := s.escapeSequence()
+			if err != nil {
+				if escapeErr == nil {
+					escapeErr = err
+				}
+				continue
+			}
+			literal.WriteRune(r)
+		default:
+			literal.WriteRune(c)
 		}
-		s.advance()
 	}
 
 	if s.isAtEnd() {
@@ -166,27 +308,187 @@ func (s *Scanner) parseString() error {
 
 	s.advance()
 
-	literal := s.source[s.start+1 : s.current-1]
-	s.addTokenWithLiteral(ast.STRING, literal)
+	if escapeErr != nil {
+		return escapeErr
+	}
+
+	tokenType := ast.STRING
+	if resumed {
+		tokenType = ast.INTERPOLATION_END
+	}
+	s.addTokenWithLiteral(tokenType, literal.String())
 
 	return nil
 }
 
-func (s *Scanner) parseNumber() {
-	for isDigit(s.peek()) {
-		s.advance()
+// escapeSequence decodes the escape sequence following a backslash in a
+// string literal.
+func (s *Scanner) escapeSequence() (rune, error) {
+	if s.isAtEnd() {
+		return 0, &ScanError{line: s.line, message: "Unterminated string."}
 	}
 
-	if s.peek() == '.' && isDigit(s.peekNext()) {
+	c := s.advance()
+	switch c {
+	case 'n':
+		return '\n', nil
+	case 't':
+		return '\t', nil
+	case 'r':
+		return '\r', nil
+	case '0':
+		return 0, nil
+	case '"', '\\', '$':
+		return c, nil
+	case 'u':
+		return s.unicodeEscape()
+	case '\n':
+		s.line++
+		return 0, &ScanError{
+			line:    s.line - 1,
+			message: "Invalid escape sequence at end of line.",
+		}
+	}
+
+	return 0, &ScanError{
+		line:    s.line,
+		message: fmt.Sprintf("Invalid escape sequence '\\%c'.", c),
+	}
+}
+
+// unicodeEscape decodes the body of a \u{XXXX} escape.
+func (s *Scanner) unicodeEscape() (rune, error) {
+	invalid := &ScanError{
+		line:    s.line,
+		message: "Invalid unicode escape sequence; expected '\\u{XXXX}'.",
+	}
+
+	if !s.match('{') {
+		return 0, invalid
+	}
+
+	start := s.current
+	for isHexDigit(s.peek()) {
 		s.advance()
-		for isDigit(s.peek()) {
+	}
+	digits := string(s.source[start:s.current])
+
+	if !s.match('}') || len(digits) == 0 || len(digits) > 6 {
+		return 0, invalid
+	}
+
+	code, _ := strconv.ParseUint(digits, 16, 32)
+	r := rune(code)
+	if !utf8.ValidRune(r) {
+		return 0, &ScanError{
+			line:    s.line,
+			message: fmt.Sprintf("Invalid unicode code point U+%s.", strings.ToUpper(digits)),
+		}
+	}
+
+	return r, nil
+}
+
+// blockComment skips a /* ... */ comment whose opening delimiter has already
+// been consumed. Block comments nest, so each "/*" must be matched by a "*/".
+func (s *Scanner) blockComment() error {
+	startLine := s.line
+	depth := 1
+
+	for depth > 0 {
+		if s.isAtEnd() {
+			return &ScanError{
+				line:    startLine,
+				message: "Unterminated block comment.",
+			}
+		}
+
+		c := s.advance()
+		switch {
+		case c == '\n':
+			s.line++
+		case c == '/' && s.match('*'):
+			depth++
+		case c == '*' && s.match('/'):
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// parseNumber scans a number literal. Literals without a fractional part
+// are integers, and may be written in hex, binary or octal with a 0x, 0b or
+// 0o prefix. Underscores may separate digits, as in 1_000_000.
+func (s *Scanner) parseNumber() error {
+	base := 10
+	if s.source[s.start] == '0' {
+		switch s.peek() {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+	}
+
+	var digits, fraction string
+	if base == 10 {
+		s.current = s.start
+		digits = s.scanDigits()
+		if s.peek() == '.' && isDigit(s.peekNext()) {
 			s.advance()
+			fraction = s.scanDigits()
 		}
+	} else {
+		s.advance()
+		digits = s.scanDigits()
+	}
+
+	invalid := &ScanError{line: s.line, message: "Invalid number literal."}
+	if !validDigits(digits, base) || (fraction != "" && !validDigits(fraction, 10)) {
+		return invalid
 	}
+	digits = strings.ReplaceAll(digits, "_", "")
+	fraction = strings.ReplaceAll(fraction, "_", "")
 
-	lexeme := s.source[s.start:s.current]
-	number, _ := strconv.ParseFloat(lexeme, 64)
+	if fraction != "" {
+		number, _ := strconv.ParseFloat(digits+"."+fraction, 64)
+		s.addTokenWithLiteral(ast.NUMBER, number)
+		return nil
+	}
+
+	number, ok := parseInteger(digits, base)
+	if !ok {
+		return invalid
+	}
 	s.addTokenWithLiteral(ast.NUMBER, number)
+	return nil
+}
+
+// scanDigits consumes a run of digits. Letters are consumed too, so that
+// malformed literals such as 0b102 or 12ab are reported as a whole.
+func (s *Scanner) scanDigits() string {
+	start := s.current
+	for isAlphaNumeric(s.peek()) {
+		s.advance()
+	}
+	return string(s.source[start:s.current])
+}
+
+// validDigits reports whether digits are all valid in base, with any
+// underscores placed singly between digits.
+func validDigits(digits string, base int) bool {
+	if digits == "" || digits[0] == '_' || digits[len(digits)-1] == '_' || strings.Contains(digits, "__") {
+		return false
+	}
+	for _, c := range digits {
+		if c != '_' && !isDigitInBase(c, base) {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *Scanner) parseIdent() {
@@ -194,7 +496,7 @@ func (s *Scanner) parseIdent() {
 		s.advance()
 	}
 
-	ident := s.source[s.start:s.current]
+	ident := string(s.source[s.start:s.current])
 	keyword, ok := ast.Keywords[ident]
 	if !ok {
 		s.addTokenWithLiteral(ast.IDENTIFIER, ident)
@@ -203,13 +505,13 @@ func (s *Scanner) parseIdent() {
 	}
 }
 
-func (s *Scanner) advance() byte {
+func (s *Scanner) advance() rune {
 	c := s.source[s.current]
 	s.current++
 	return c
 }
 
-func (s *Scanner) match(c byte) bool {
+func (s *Scanner) match(c rune) bool {
 	if s.isAtEnd() {
 		return false
 	}
@@ -220,14 +522,14 @@ func (s *Scanner) match(c byte) bool {
 	return true
 }
 
-func (s *Scanner) peek() byte {
+func (s *Scanner) peek() rune {
 	if s.isAtEnd() {
 		return 0
 	}
 	return s.source[s.current]
 }
 
-func (s *Scanner) peekNext() byte {
+func (s *Scanner) peekNext() rune {
 	if s.current+1 >= len(s.source) {
 		return 0
 	}
@@ -238,14 +540,32 @@ func (s *Scanner) isAtEnd() bool {
 	return s.current >= len(s.source)
 }
 
-func isDigit(c byte) bool {
+func isDigit(c rune) bool {
 	return c >= '0' && c <= '9'
 }
 
-func isAlpha(c byte) bool {
-	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+func isDigitInBase(c rune, base int) bool {
+	switch base {
+	case 2:
+		return c == '0' || c == '1'
+	case 8:
+		return c >= '0' && c <= '7'
+	case 16:
+		return isHexDigit(c)
+	}
+	return isDigit(c)
+}
+
+func isHexDigit(c rune) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isAlpha reports whether c may start an identifier. Any Unicode letter is
+// accepted, not only ASCII.
+func isAlpha(c rune) bool {
+	return unicode.IsLetter(c) || c == '_'
 }
 
-func isAlphaNumeric(c byte) bool {
+func isAlphaNumeric(c rune) bool {
 	return isAlpha(c) || isDigit(c)
 }
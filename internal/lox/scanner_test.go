@@ -0,0 +1,176 @@
+package lox
+
+import (
+	"github.com/LucDeCaf/go-lox/internal/lox/ast"
+	"strings"
+	"testing"
+)
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"a\tb"`, "a\tb"},
+		{`"line\nbreak\r"`, "line\nbreak\r"},
+		{`"\"quoted\""`, `"quoted"`},
+		{`"back\\slash"`, `back\slash`},
+		{`"nul\0"`, "nul\x00"},
+		{`"\u{e9}\u{1F600}"`, "é😀"},
+		{`"é😀"`, "é😀"},
+	}
+
+	for _, test := range tests {
+		tokens, ok := NewScanner().scanTokens(test.source)
+		if !ok {
+			t.Errorf("scan %s failed", test.source)
+			continue
+		}
+		if tokens[0].Type != ast.STRING || tokens[0].Literal != test.want {
+			t.Errorf("scan %s = %v %q, want STRING %q", test.source, tokens[0].Type, tokens[0].Literal, test.want)
+		}
+	}
+}
+
+func TestStringEscapeErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"\q"`, `Invalid escape sequence '\q'.`},
+		{`"\u{110000}"`, "Invalid unicode code point U+110000."},
+		{`"\u{D800}"`, "Invalid unicode code point U+D800."},
+		{`"\u{zz}"`, `Invalid unicode escape sequence`},
+		{`"\u1234"`, `Invalid unicode escape sequence`},
+		{`"open`, "Unterminated string."},
+	}
+
+	for _, test := range tests {
+		scanner := NewScanner()
+		if _, ok := scanner.scanTokens(test.source); ok {
+			t.Errorf("scan %s succeeded, want an error", test.source)
+			continue
+		}
+		if len(scanner.errors) == 0 || !strings.Contains(scanner.errors[0].Error(), test.want) {
+			t.Errorf("scan %s errors = %v, want %q", test.source, scanner.errors, test.want)
+		}
+	}
+}
+
+func TestUnicodeSource(t *testing.T) {
+	tokens, ok := NewScanner().scanTokens("var café = \"☕\"; // ünïcödé comment\nπ;")
+	if !ok {
+		t.Fatal("scan failed")
+	}
+
+	want := []struct {
+		kind   ast.TokenType
+		lexeme string
+		line   int
+	}{
+		{ast.VAR, "var", 1},
+		{ast.IDENTIFIER, "café", 1},
+		{ast.EQUAL, "=", 1},
+		{ast.STRING, `"☕"`, 1},
+		{ast.SEMICOLON, ";", 1},
+		{ast.IDENTIFIER, "π", 2},
+		{ast.SEMICOLON, ";", 2},
+		{ast.EOF, "", 2},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens %v, want %d", len(tokens), tokens, len(want))
+	}
+	for idx, token := range tokens {
+		if token.Type != want[idx].kind || token.Lexeme != want[idx].lexeme || token.Line != want[idx].line {
+			t.Errorf("token %d = %v on line %d, want %v %q on line %d",
+				idx, token, token.Line, want[idx].kind, want[idx].lexeme, want[idx].line)
+		}
+	}
+
+	i := NewInterpreter()
+	if got := eval(t, i, `"é😀".len()`); got != int64(2) {
+		t.Errorf(`"é😀".len() = %v, want 2`, got)
+	}
+}
+
+func TestBlockComments(t *testing.T) {
+	tests := []struct {
+		source string
+		want   []string
+		// line is the line of the last token.
+		line int
+	}{
+		{"1 /* comment */ 2", []string{"1", "2"}, 1},
+		{"1 /* outer /* inner */ still comment */ 2", []string{"1", "2"}, 1},
+		{"1 /* a /* b /* c */ */ */ 2", []string{"1", "2"}, 1},
+		{"1 /* // not a line comment */ 2", []string{"1", "2"}, 1},
+		{"1 /* one\ntwo\nthree */ 2", []string{"1", "2"}, 3},
+		{"1 /* \n /* \n */ \n */\n2", []string{"1", "2"}, 5},
+		{"1 /**/ 2 /***/ 3", []string{"1", "2", "3"}, 1},
+		{"1 */ 2", []string{"1", "*", "/", "2"}, 1},
+	}
+
+	for _, test := range tests {
+		tokens, ok := NewScanner().scanTokens(test.source)
+		if !ok {
+			t.Errorf("scan %q failed", test.source)
+			continue
+		}
+		tokens = tokens[:len(tokens)-1]
+		got := make([]string, len(tokens))
+		for idx, token := range tokens {
+			got[idx] = token.Lexeme
+		}
+		if strings.Join(got, " ") != strings.Join(test.want, " ") {
+			t.Errorf("scan %q = %v, want %v", test.source, got, test.want)
+		}
+		if last := tokens[len(tokens)-1].Line; last != test.line {
+			t.Errorf("scan %q: last token on line %d, want %d", test.source, last, test.line)
+		}
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	tests := []struct {
+		source string
+		line   int
+	}{
+		{"/* open", 1},
+		{"1;\n\n/* open\nmore\nlines", 3},
+		{"/* outer\n/* inner */\nstill open", 1},
+		{"1;\n/* a */ /* b\n", 2},
+	}
+
+	for _, test := range tests {
+		scanner := NewScanner()
+		if _, ok := scanner.scanTokens(test.source); ok {
+			t.Errorf("scan %q succeeded, want an error", test.source)
+			continue
+		}
+		err, ok := scanner.errors[0].(*ScanError)
+		if !ok || err.message != "Unterminated block comment." || err.line != test.line {
+			t.Errorf("scan %q errors = %v, want an unterminated comment on line %d", test.source, scanner.errors, test.line)
+		}
+	}
+}
+
+func TestInterpolationTokens(t *testing.T) {
+	tokens, ok := NewScanner().scanTokens(`"a${x "s"}b${y}c"`)
+	if !ok {
+		t.Fatal("scan failed")
+	}
+
+	want := []ast.TokenType{
+		ast.INTERPOLATION, ast.IDENTIFIER, ast.STRING,
+		ast.INTERPOLATION_MIDDLE, ast.IDENTIFIER,
+		ast.INTERPOLATION_END, ast.EOF,
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("scanned %v, want %v", tokens, want)
+	}
+	for idx, token := range tokens {
+		if token.Type != want[idx] {
+			t.Errorf("token %d = %v, want %v", idx, token.Type, want[idx])
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxStringLength caps the size in bytes of strings built by methods such
+// as repeat, so that a script cannot crash the host by exhausting memory.
+const maxStringLength = 1 << 28
+
+// stringMethod returns the built-in method name bound to s. Positions and
+// lengths are counted in characters (runes), not bytes.
+func stringMethod(s string, name string) (any, bool) {
+	var fn func(*Interpreter, []any) (any, error)
+	arity := 0
+
+	switch name {
+	case "len":
+		fn = func(i *Interpreter, args []any) (any, error) {
+			return int64(utf8.RuneCountInString(s)), nil
+		}
+	case "substring":
+		arity = -1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			return substring(s, args)
+		}
+	case "indexOf":
+		arity = 1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			sub, err := stringArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			idx := strings.Index(s, sub)
+			if idx < 0 {
+				return int64(-1), nil
+			}
+			return int64(utf8.RuneCountInString(s[:idx])), nil
+		}
+	case "split":
+		arity = 1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			sep, err := stringArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			parts := strings.Split(s, sep)
+			elements := make([]any, len(parts))
+			for idx, part := range parts {
+				elements[idx] = part
+			}
+			return NewLoxList(elements), nil
+		}
+	case "join":
+		arity = 1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			list, ok := args[0].(*LoxList)
+			if !ok {
+				return nil, &nativeError{message: "Argument to join must be a list."}
+			}
+			parts := make([]string, len(list.elements))
+			for idx, element := range list.elements {
+				parts[idx] = stringify(element)
+			}
+			return strings.Join(parts, s), nil
+		}
+	case "trim":
+		fn = func(i *Interpreter, args []any) (any, error) {
+			return strings.TrimSpace(s), nil
+		}
+	case "upper":
+		fn = func(i *Interpreter, args []any) (any, error) {
+			return strings.ToUpper(s), nil
+		}
+	case "lower":
+		fn = func(i *Interpreter, args []any) (any, error) {
+			return strings.ToLower(s), nil
+		}
+	case "replace":
+		arity = 2
+		fn = func(i *Interpreter, args []any) (any, error) {
+			old, err := stringArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			replacement, err := stringArg(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+			return strings.ReplaceAll(s, old, replacement), nil
+		}
+	case "startsWith":
+		arity = 1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			prefix, err := stringArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return strings.HasPrefix(s, prefix), nil
+		}
+	case "endsWith":
+		arity = 1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			suffix, err := stringArg(name, args[0])
+			if err != nil {
+				return nil, err
+			}
+			return strings.HasSuffix(s, suffix), nil
+		}
+	case "repeat":
+		arity = 1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			if count, ok := toBig(args[0]); !ok || count.Sign() < 0 {
+				return nil, &nativeError{message: "Repeat count must be a non-negative integer."}
+			}
+			n, ok := toInt(args[0])
+			if !ok || (len(s) > 0 && n > maxStringLength/int64(len(s))) {
+				return nil, &nativeError{message: "Repeat count too large."}
+			}
+			return strings.Repeat(s, int(n)), nil
+		}
+	case "charCodeAt":
+		arity = 1
+		fn = func(i *Interpreter, args []any) (any, error) {
+			runes := []rune(s)
+			n, ok := toInt(args[0])
+			if !ok && !isInteger(args[0]) {
+				return nil, &nativeError{message: "String index must be an integer."}
+			}
+			if !ok || n < 0 || n >= int64(len(runes)) {
+				return nil, &nativeError{message: "String index out of range."}
+			}
+			return int64(runes[n]), nil
+		}
+	case "toNumber":
+		fn = func(i *Interpreter, args []any) (any, error) {
+			n, ok := parseNumber(s)
+			if !ok {
+				return nil, nil
+			}
+			return n, nil
+		}
+	default:
+		return nil, false
+	}
+
+	return NewNativeFunction(name, arity, fn), true
+}
+
+// numberMethod returns the built-in method name bound to the number n.
+func numberMethod(n any, name string) (any, bool) {
+	switch name {
+	case "toFixed":
+		return NewNativeFunction(name, 1, func(i *Interpreter, args []any) (any, error) {
+			digits, ok := toInt(args[0])
+			if !ok || digits < 0 || digits > 100 {
+				return nil, &nativeError{message: "Precision must be an integer between 0 and 100."}
+			}
+			if f, ok := n.(float64); ok {
+				if math.IsInf(f, 0) || math.IsNaN(f) {
+					return formatFloat(f), nil
+				}
+				return strconv.FormatFloat(f, 'f', int(digits), 64), nil
+			}
+			b, _ := toBig(n)
+			return new(big.Float).SetInt(b).Text('f', int(digits)), nil
+		}), true
+	case "toString":
+		return NewNativeFunction(name, 0, func(i *Interpreter, args []any) (any, error) {
+			return stringify(n), nil
+		}), true
+	}
+	return nil, false
+}
+
+// substring implements s.substring(start) and s.substring(start, end).
+func substring(s string, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, &nativeError{message: fmt.Sprintf("Expected 1 or 2 arguments but got %d.", len(args))}
+	}
+
+	runes := []rune(s)
+	bounds := []int{0, len(runes)}
+	for idx, arg := range args {
+		n, ok := toInt(arg)
+		if !ok && !isInteger(arg) {
+			return nil, &nativeError{message: "Substring bounds must be integers."}
+		}
+		if !ok || n < 0 || n > int64(len(runes)) {
+			return nil, &nativeError{message: "Substring bounds out of range."}
+		}
+		bounds[idx] = int(n)
+	}
+	if bounds[0] > bounds[1] {
+		return nil, &nativeError{message: "Substring start must not be greater than end."}
+	}
+
+	return string(runes[bounds[0]:bounds[1]]), nil
+}
+
+func stringArg(name string, arg any) (string, error) {
+	s, ok := arg.(string)
+	if !ok {
+		return "", &nativeError{message: "Argument to " + name + " must be a string."}
+	}
+	return s, nil
+}
+
+func nativeFromCharCode(i *Interpreter, args []any) (any, error) {
+	code, ok := toInt(args[0])
+	if !ok || code < 0 || code > utf8.MaxRune || !utf8.ValidRune(rune(code)) {
+		return nil, &nativeError{message: "Character code must be a valid code point."}
+	}
+	return string(rune(code)), nil
+}
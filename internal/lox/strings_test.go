@@ -0,0 +1,81 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStringMethods(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"héllo".len()`, "5"},
+		{`"".len()`, "0"},
+		{`"héllo".substring(1)`, "éllo"},
+		{`"héllo".substring(1, 3)`, "él"},
+		{`"héllo".substring(5)`, ""},
+		{`"héllo".indexOf("l")`, "2"},
+		{`"héllo".indexOf("z")`, "-1"},
+		{`"a,b,,c".split(",")`, "[a, b, , c]"},
+		{`"abc".split("")`, "[a, b, c]"},
+		{`", ".join([1, "two", nil])`, "1, two, nil"},
+		{`"-".join([])`, ""},
+		{`"  padded \t\n".trim()`, "padded"},
+		{`"café".upper()`, "CAFÉ"},
+		{`"ÉCOLE".lower()`, "école"},
+		{`"aaa".replace("a", "bb")`, "bbbbbb"},
+		{`"hello".startsWith("he")`, "true"},
+		{`"hello".endsWith("he")`, "false"},
+		{`"ab".repeat(3)`, "ababab"},
+		{`"ab".repeat(0)`, ""},
+		{`"".repeat(1000000000000)`, ""},
+		{`"é".charCodeAt(0)`, "233"},
+		{`fromCharCode(128512)`, "😀"},
+		{`"42".toNumber()`, "42"},
+		{`" 1.5 ".trim().toNumber()`, "1.5"},
+		{`"abc".toNumber()`, "nil"},
+		{`(1.005).toFixed(1)`, "1.0"},
+		{`(2).toFixed(2)`, "2.00"},
+		{`(0.5).toString()`, "0.5"},
+	}
+
+	i := NewInterpreter()
+	for _, test := range tests {
+		if got := stringify(eval(t, i, test.source)); got != test.want {
+			t.Errorf("%s = %q, want %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestStringMethodErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"abc".substring(-1);`, "Substring bounds out of range."},
+		{`"abc".substring(0, 4);`, "Substring bounds out of range."},
+		{`"abc".substring(2, 1);`, "Substring start must not be greater than end."},
+		{`"abc".substring(0.5);`, "Substring bounds must be integers."},
+		{`"abc".indexOf(1);`, "Argument to indexOf must be a string."},
+		{`",".join("abc");`, "Argument to join must be a list."},
+		{`"abc".charCodeAt(3);`, "String index out of range."},
+		{`"abc".charCodeAt(-1);`, "String index out of range."},
+		{`"abc".charCodeAt("0");`, "String index must be an integer."},
+		{`"ab".repeat(-1);`, "Repeat count must be a non-negative integer."},
+		{`"ab".repeat(1.5);`, "Repeat count must be a non-negative integer."},
+		{`"ab".repeat(100000000000000000000);`, "Repeat count too large."},
+		{fmt.Sprintf(`"ab".repeat(%d);`, maxStringLength/2+1), "Repeat count too large."},
+		{fmt.Sprintf(`"a".repeat(%d);`, maxStringLength+1), "Repeat count too large."},
+		{`fromCharCode(55296);`, "Character code must be a valid code point."},
+		{`(1).toFixed(101);`, "Precision must be an integer between 0 and 100."},
+	}
+
+	for _, test := range tests {
+		err := run(t, NewInterpreter(), test.source)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want %q", test.source, err, test.want)
+		}
+	}
+}
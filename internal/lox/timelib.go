@@ -0,0 +1,259 @@
+package lox
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Clock is the time source for the time natives. Hosts can substitute a fake
+// clock with Lox.SetClock to run scripts deterministically.
+type Clock interface {
+	Now() time.Time
+	// Sleep blocks for d, returning early with ctx's error if it is
+	// cancelled.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock reads the real time.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetClock sets the time source used by the time natives.
+func (i *Interpreter) SetClock(clock Clock) {
+	i.clock = clock
+}
+
+// Timestamps and durations are numbers of milliseconds, so they can be
+// combined with ordinary arithmetic.
+func (i *Interpreter) defineTimeNatives() {
+	i.globals.Define("clock", NewNativeFunction("clock", 0, timeClock))
+	i.globals.Define("now", NewNativeFunction("now", 0, timeNow))
+	i.globals.Define("sleep", NewNativeFunction("sleep", 1, timeSleep))
+	i.globals.Define("formatTime", NewNativeFunction("formatTime", -1, timeFormat))
+	i.globals.Define("parseTime", NewNativeFunction("parseTime", -1, timeParse))
+}
+
+// timeClock returns the current time in seconds, as in the reference
+// implementation.
+func timeClock(i *Interpreter, args []any) (any, error) {
+	return float64(i.clock.Now().UnixNano()) / float64(time.Second), nil
+}
+
+// timeNow returns the current time in milliseconds since the Unix epoch.
+func timeNow(i *Interpreter, args []any) (any, error) {
+	return i.clock.Now().UnixMilli(), nil
+}
+
+func timeSleep(i *Interpreter, args []any) (any, error) {
+	ms, ok := toFloat(args[0])
+	if !ok || ms < 0 || math.IsNaN(ms) {
+		return nil, &nativeError{message: "Sleep duration must be a non-negative number."}
+	}
+	if err := i.clock.Sleep(i.ctx, time.Duration(ms*float64(time.Millisecond))); err != nil {
+		return nil, cancelled(err)
+	}
+	return nil, nil
+}
+
+// timeFormat implements formatTime(ms) and formatTime(ms, layout). The
+// layout uses strftime directives and defaults to ISO 8601.
+func timeFormat(i *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, &nativeError{message: fmt.Sprintf("Expected 1 or 2 arguments but got %d.", len(args))}
+	}
+
+	ms, ok := toFloat(args[0])
+	if !ok || math.IsNaN(ms) || math.IsInf(ms, 0) {
+		return nil, &nativeError{message: "Timestamp must be a number of milliseconds."}
+	}
+
+	t := time.UnixMilli(int64(ms)).In(i.clock.Now().Location())
+	if len(args) == 1 {
+		return t.Format(time.RFC3339), nil
+	}
+
+	fields, err := timeLayout(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	// Each directive is formatted on its own, so literal text in the layout
+	// is never read as part of a Go layout.
+	var text strings.Builder
+	for _, field := range fields {
+		switch field.directive {
+		case 0:
+			text.WriteString(field.literal)
+		case 'L':
+			fmt.Fprintf(&text, "%03d", t.Nanosecond()/int(time.Millisecond))
+		default:
+			text.WriteString(t.Format(strftimeDirectives[field.directive]))
+		}
+	}
+	return text.String(), nil
+}
+
+// timeParse implements parseTime(text) and parseTime(text, layout),
+// returning milliseconds since the Unix epoch.
+func timeParse(i *Interpreter, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, &nativeError{message: fmt.Sprintf("Expected 1 or 2 arguments but got %d.", len(args))}
+	}
+
+	text, ok := args[0].(string)
+	if !ok {
+		return nil, &nativeError{message: "Time to parse must be a string."}
+	}
+
+	layout, value := time.RFC3339, text
+	if len(args) == 2 {
+		fields, err := timeLayout(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if layout, value, err = splitTime(fields, text); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := time.ParseInLocation(layout, value, i.clock.Now().Location())
+	if err != nil {
+		return nil, &nativeError{message: "Could not parse time: " + err.Error()}
+	}
+	return t.UnixMilli(), nil
+}
+
+// splitTime matches the literal fields of a layout against text and cuts
+// out the text of each directive. It returns a Go layout made only of the
+// directives and the matching text, so that literals are compared verbatim.
+func splitTime(fields []timeField, text string) (string, string, error) {
+	var layout, value []string
+	for _, field := range fields {
+		if field.directive == 0 {
+			if !strings.HasPrefix(text, field.literal) {
+				return "", "", &nativeError{message: fmt.Sprintf("Could not parse time: expected %q.", field.literal)}
+			}
+			text = text[len(field.literal):]
+			continue
+		}
+
+		n := directiveWidth(field.directive, text)
+		layout = append(layout, strftimeDirectives[field.directive])
+		if field.directive == 'L' {
+			value = append(value, "."+text[:n])
+		} else {
+			value = append(value, text[:n])
+		}
+		text = text[n:]
+	}
+	if text != "" {
+		return "", "", &nativeError{message: fmt.Sprintf("Could not parse time: unexpected %q.", text)}
+	}
+	return strings.Join(layout, " "), strings.Join(value, " "), nil
+}
+
+// directiveWidth returns the length of the prefix of text that a directive
+// can match. The text is validated when the time is parsed.
+func directiveWidth(directive byte, text string) int {
+	width := 0
+	switch directive {
+	case 'Y':
+		width = 4
+	case 'L', 'a', 'b':
+		width = 3
+	case 'z':
+		width = 5
+	case 'A', 'B', 'Z':
+		for width < len(text) && unicode.IsLetter(rune(text[width])) {
+			width++
+		}
+	default:
+		width = 2
+	}
+	return min(width, len(text))
+}
+
+// strftimeDirectives gives the Go layout for each directive. A layout holds
+// a single directive, so that literal text never reaches Go's parser; '%L'
+// needs the leading dot for Go to read it as milliseconds.
+var strftimeDirectives = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'H': "15",
+	'I': "03",
+	'M': "04",
+	'S': "05",
+	'L': ".000",
+	'p': "PM",
+	'a': "Mon",
+	'A': "Monday",
+	'b': "Jan",
+	'B': "January",
+	'z': "-0700",
+	'Z': "MST",
+}
+
+// timeField is a piece of a strftime layout: either a directive or, when
+// directive is zero, literal text.
+type timeField struct {
+	directive byte
+	literal   string
+}
+
+// timeLayout splits a strftime layout argument into its fields.
+func timeLayout(arg any) ([]timeField, error) {
+	format, ok := arg.(string)
+	if !ok {
+		return nil, &nativeError{message: "Time layout must be a string."}
+	}
+
+	var fields []timeField
+	var literal strings.Builder
+	for idx := 0; idx < len(format); idx++ {
+		if format[idx] != '%' {
+			literal.WriteByte(format[idx])
+			continue
+		}
+		idx++
+		if idx >= len(format) {
+			return nil, &nativeError{message: "Time layout ends with an incomplete directive."}
+		}
+		if format[idx] == '%' {
+			literal.WriteByte('%')
+			continue
+		}
+		if _, ok := strftimeDirectives[format[idx]]; !ok {
+			return nil, &nativeError{message: "Unknown time layout directive '%" + string(format[idx]) + "'."}
+		}
+		if literal.Len() > 0 {
+			fields = append(fields, timeField{literal: literal.String()})
+			literal.Reset()
+		}
+		fields = append(fields, timeField{directive: format[idx]})
+	}
+	if literal.Len() > 0 {
+		fields = append(fields, timeField{literal: literal.String()})
+	}
+	return fields, nil
+}
@@ -0,0 +1,138 @@
+package lox
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// fakeClock starts at a fixed instant and advances only when slept on.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.now = c.now.Add(d)
+	return nil
+}
+
+func newTestClock() *fakeClock {
+	return &fakeClock{now: time.Date(2023, time.November, 14, 22, 13, 20, 123e6, time.UTC)}
+}
+
+func TestNowAndSleep(t *testing.T) {
+	i := NewInterpreter()
+	i.SetClock(newTestClock())
+
+	if got := eval(t, i, "now()"); got != int64(1700000000123) {
+		t.Errorf("now() = %v, want 1700000000123", got)
+	}
+	if got, ok := eval(t, i, "clock()").(float64); !ok || math.Abs(got-1700000000.123) > 1e-6 {
+		t.Errorf("clock() = %v, want 1700000000.123", got)
+	}
+	if got := eval(t, i, "sleep(1500) ?? now()"); got != int64(1700000001623) {
+		t.Errorf("now() after sleep(1500) = %v, want 1700000001623", got)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`formatTime(now())`, "2023-11-14T22:13:20Z"},
+		{`formatTime(now(), "%Y-%m-%d %H:%M:%S.%L")`, "2023-11-14 22:13:20.123"},
+		{`formatTime(now(), "%a %A %b %B %d %y %I%p %z %Z")`, "Tue Tuesday Nov November 14 23 10PM +0000 UTC"},
+		{`formatTime(now() + 5, "%L")`, "128"},
+		// Literal text is copied verbatim, even where it spells a Go layout.
+		{`formatTime(now(), "Q1 Mon Jan 2 06 15 PM MST %%Y")`, "Q1 Mon Jan 2 06 15 PM MST %Y"},
+	}
+
+	i := NewInterpreter()
+	i.SetClock(newTestClock())
+	for _, test := range tests {
+		if got := eval(t, i, test.source); got != test.want {
+			t.Errorf("%s = %q, want %q", test.source, got, test.want)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		source string
+		want   int64
+	}{
+		{`parseTime("2023-11-14T22:13:20Z")`, 1700000000000},
+		{`parseTime("Q1 2023-11-14 22:13:20.123", "Q1 %Y-%m-%d %H:%M:%S.%L")`, 1700000000123},
+		{`parseTime("Tue, Nov 14 2023 10PM", "%a, %b %d %Y %I%p")`, 1699999200000},
+		{`parseTime(formatTime(now(), "%B %d %Y"), "%B %d %Y")`, 1699920000000},
+	}
+
+	i := NewInterpreter()
+	i.SetClock(newTestClock())
+	for _, test := range tests {
+		if got := eval(t, i, test.source); got != test.want {
+			t.Errorf("%s = %v, want %d", test.source, got, test.want)
+		}
+	}
+
+	if err := run(t, i, `parseTime("2023-11-14", "%Y/%m/%d");`); err == nil {
+		t.Error("parseTime with mismatched literal succeeded")
+	}
+}
+
+func TestSleepCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	i := NewInterpreter()
+	i.SetClock(newTestClock())
+	i.SetContext(ctx)
+	i.SetFile("test.lox")
+
+	err := run(t, i, "try {\n  sleep(10);\n} catch (e) {\n  print e;\n}")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("error = %T, want *RuntimeError", err)
+	}
+	if line := runtimeErr.token.Line; line != 2 {
+		t.Errorf("error line = %d, want 2", line)
+	}
+	want := []StackFrame{
+		{Function: "sleep", File: "test.lox", Line: 2},
+		{Function: "<script>", File: "test.lox", Line: 2},
+	}
+	if got := runtimeErr.StackTrace(); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("stack = %v, want %v", got, want)
+	}
+}
+
+func TestForInCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	i := NewInterpreter()
+	i.SetContext(ctx)
+
+	err := run(t, i, "for (x in [1, 2, 3]) {\n}")
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want a RuntimeError wrapping context.Canceled", err)
+	}
+	if line := runtimeErr.token.Line; line != 1 {
+		t.Errorf("error line = %d, want 1", line)
+	}
+}
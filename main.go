@@ -5,20 +5,35 @@ import (
 	"github.com/LucDeCaf/go-lox/internal/lox"
 	"github.com/LucDeCaf/go-lox/internal/lox/error_reporters"
 	"os"
+	"path/filepath"
 )
 
 func main() {
 	args := os.Args[1:]
 
-	lox := lox.NewLox()
-	lox.RegisterErrorReporter(error_reporters.NewStdoutReporter())
+	l := lox.NewLox()
+	l.RegisterErrorReporter(error_reporters.NewStdoutReporter())
+	if path := os.Getenv("LOX_PATH"); path != "" {
+		l.SetModulePath(filepath.SplitList(path))
+	}
+
+	if len(args) > 0 && args[0] == "run" {
+		args = args[1:]
+		if len(args) == 0 {
+			fmt.Println("Usage: lox run [script] [args...]")
+			os.Exit(64)
+		}
+	}
 
 	switch len(args) {
 	case 0:
-		lox.RunPrompt()
-	case 1:
-		lox.RunFile(args[0])
+		os.Exit(l.RunPrompt())
 	default:
-		fmt.Println("Usage: lox [script]")
+		status, err := l.RunFile(lox.HostFS{}, args[0], args[1:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(66)
+		}
+		os.Exit(status)
 	}
 }